@@ -0,0 +1,152 @@
+package day01
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/icholy/advent"
+)
+
+func ParseInput(r io.Reader) ([]int64, error) {
+	var deltas []int64
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		x, err := strconv.ParseInt(sc.Text(), 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		deltas = append(deltas, x)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return deltas, nil
+}
+
+func PartOne(deltas []int64) int64 {
+	var freq int64
+	for _, x := range deltas {
+		freq += x
+	}
+	return freq
+}
+
+func PartTwo(deltas []int64) (int64, bool) {
+	return FirstRepeat(deltas)
+}
+
+// FirstRepeat returns the first frequency reached twice while
+// applying deltas over and over. It returns false if no frequency
+// is ever reached twice.
+func FirstRepeat(deltas []int64) (int64, bool) {
+	freq, _, ok := FindRepeat(deltas)
+	return freq, ok
+}
+
+// FindRepeat is like FirstRepeat but also returns the pass over deltas
+// during which the repeat happened, starting from 1.
+//
+// Only the first pass is simulated. After that, every frequency is
+// one of the first pass's frequencies plus a multiple of the drift
+// (the frequency at the end of the pass). So a frequency can only come
+// back around if it's congruent to another one modulo the drift, and
+// the first one to do so is the closest in the direction of the drift.
+func FindRepeat(deltas []int64) (freq int64, pass int, ok bool) {
+	var (
+		sums = []int64{0}
+		seen = map[int64]bool{0: true}
+	)
+	for _, x := range deltas {
+		freq += x
+		if seen[freq] {
+			return freq, 1, true
+		}
+		seen[freq] = true
+		sums = append(sums, freq)
+	}
+	// a non-empty pass with no drift ends where it started,
+	// so it would already have been caught above.
+	drift := freq
+	if drift == 0 {
+		return 0, 0, false
+	}
+	step := Abs(drift)
+
+	// order the sums by residue and then in the direction of the drift.
+	// This puts each frequency right before the one it'll reach first.
+	order := make([]int, len(sums))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(a, b int) bool {
+		x, y := sums[order[a]], sums[order[b]]
+		if rx, ry := Mod(x, step), Mod(y, step); rx != ry {
+			return rx < ry
+		}
+		if drift > 0 {
+			return x < y
+		}
+		return x > y
+	})
+
+	// the starting frequency is never reached by applying a delta,
+	// so it can be repeated but can't do the repeating.
+	var (
+		n    = int64(len(deltas))
+		best = int64(-1)
+	)
+	for a := 0; a+1 < len(order); a++ {
+		from, to := order[a], order[a+1]
+		if from == 0 || Mod(sums[from], step) != Mod(sums[to], step) {
+			continue
+		}
+		// the number of passes needed to drift from one to the other
+		m := Abs(sums[to]-sums[from]) / step
+		if t := m*n + int64(from); best == -1 || t < best {
+			best = t
+			freq = sums[to]
+			pass = int(m) + 1
+		}
+	}
+	if best == -1 {
+		return 0, 0, false
+	}
+	return freq, pass, true
+}
+
+func Abs(x int64) int64 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// Mod returns the non-negative remainder of x divided by m
+func Mod(x, m int64) int64 {
+	return (x%m + m) % m
+}
+
+type Solver struct{}
+
+func (Solver) Parse(r io.Reader) (advent.Input, error) {
+	return ParseInput(r)
+}
+
+func (Solver) PartOne(in advent.Input) (string, error) {
+	return strconv.FormatInt(PartOne(in.([]int64)), 10), nil
+}
+
+func (Solver) PartTwo(in advent.Input) (string, error) {
+	freq, ok := PartTwo(in.([]int64))
+	if !ok {
+		return "", fmt.Errorf("no frequency is reached twice")
+	}
+	return strconv.FormatInt(freq, 10), nil
+}
+
+func init() {
+	advent.Register(1, Solver{})
+}
@@ -0,0 +1,65 @@
+package day01
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// simulate applies the deltas pass by pass until a frequency repeats
+// or the passes run out
+func simulate(deltas []int64, passes int) (int64, int, bool) {
+	var (
+		freq int64
+		seen = map[int64]bool{0: true}
+	)
+	for pass := 1; pass <= passes; pass++ {
+		for _, x := range deltas {
+			freq += x
+			if seen[freq] {
+				return freq, pass, true
+			}
+			seen[freq] = true
+		}
+	}
+	return 0, 0, false
+}
+
+func TestFindRepeat(t *testing.T) {
+	tests := []struct {
+		deltas []int64
+		freq   int64
+		pass   int
+		ok     bool
+	}{
+		{[]int64{+1, -1}, 0, 1, true},
+		{[]int64{+3, +3, +4, -2, -4}, 10, 2, true},
+		{[]int64{-6, +3, +8, +5, -6}, 5, 3, true},
+		{[]int64{+7, +7, -2, -7, -4}, 14, 3, true},
+		{[]int64{+1}, 0, 0, false},
+		{[]int64{+3, +3}, 0, 0, false},
+		{[]int64{-2, +1}, -2, 2, true},
+		{nil, 0, 0, false},
+	}
+	for _, tt := range tests {
+		freq, pass, ok := FindRepeat(tt.deltas)
+		if freq != tt.freq || pass != tt.pass || ok != tt.ok {
+			t.Errorf("FindRepeat(%v) = %d, %d, %t, want %d, %d, %t", tt.deltas, freq, pass, ok, tt.freq, tt.pass, tt.ok)
+		}
+	}
+}
+
+func TestFindRepeatSimulated(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		deltas := make([]int64, 1+r.Intn(6))
+		for j := range deltas {
+			deltas[j] = int64(r.Intn(21) - 10)
+		}
+		// with deltas this small every repeat happens within 100 passes
+		want, wantPass, wantOK := simulate(deltas, 100)
+		got, gotPass, gotOK := FindRepeat(deltas)
+		if got != want || gotPass != wantPass || gotOK != wantOK {
+			t.Fatalf("FindRepeat(%v) = %d, %d, %t, want %d, %d, %t", deltas, got, gotPass, gotOK, want, wantPass, wantOK)
+		}
+	}
+}
@@ -1,10 +1,12 @@
-package main
+package day02
 
 import (
 	"bufio"
 	"fmt"
-	"log"
-	"os"
+	"io"
+	"strconv"
+
+	"github.com/icholy/advent"
 )
 
 func ToRunes(s string) []rune {
@@ -115,27 +117,56 @@ func (t *Table) Similar(s string) (string, bool) {
 	return "", false
 }
 
-func main() {
-	f, err := os.Open("input.txt")
-	if err != nil {
-		log.Fatal(err)
+func ParseInput(r io.Reader) ([]string, error) {
+	var ids []string
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		ids = append(ids, sc.Text())
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
 	}
-	defer f.Close()
+	return ids, nil
+}
 
+func PartOne(ids []string) int {
 	var chk Checksum
-	tbl := make(Table)
-	sc := bufio.NewScanner(f)
-	for sc.Scan() {
-		id := sc.Text()
-
-		// update the checksum
+	for _, id := range ids {
 		chk.Update(id)
+	}
+	return chk.Sum()
+}
 
+func PartTwo(ids []string) (string, bool) {
+	tbl := make(Table)
+	for _, id := range ids {
 		// check for a similar id
 		if s, ok := tbl.Similar(id); ok {
-			fmt.Printf("Common: %s\n", Common(id, s))
+			return Common(id, s), true
 		}
 		tbl.Insert(id)
 	}
-	fmt.Printf("Checksum: %d\n", chk.Sum())
+	return "", false
+}
+
+type Solver struct{}
+
+func (Solver) Parse(r io.Reader) (advent.Input, error) {
+	return ParseInput(r)
+}
+
+func (Solver) PartOne(in advent.Input) (string, error) {
+	return strconv.Itoa(PartOne(in.([]string))), nil
+}
+
+func (Solver) PartTwo(in advent.Input) (string, error) {
+	common, ok := PartTwo(in.([]string))
+	if !ok {
+		return "", fmt.Errorf("no similar ids")
+	}
+	return common, nil
+}
+
+func init() {
+	advent.Register(2, Solver{})
 }
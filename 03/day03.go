@@ -1,14 +1,15 @@
-package main
+package day03
 
 import (
 	"bufio"
 	"fmt"
 	"image"
-	"log"
+	"io"
 	"os"
 	"regexp"
 	"strconv"
 
+	"github.com/icholy/advent"
 	"github.com/icholy/draw"
 )
 
@@ -146,33 +147,43 @@ func Draw(rr []image.Rectangle) error {
 	return cv.WriteTo(os.Stdout)
 }
 
-func main() {
-	f, err := os.Open("input.txt")
-	if err != nil {
-		log.Fatal(err)
-	}
-	defer f.Close()
-
-	// find the overlapped regions
-	var claims, overlaps []image.Rectangle
-	sc := bufio.NewScanner(f)
+func ParseInput(r io.Reader) ([]image.Rectangle, error) {
+	var claims []image.Rectangle
+	sc := bufio.NewScanner(r)
 	for sc.Scan() {
 		claim, err := ParseClaim(sc.Text())
 		if err != nil {
-			log.Fatal(err)
+			return nil, err
 		}
-		for _, c := range claims {
+		claims = append(claims, claim)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// Overlaps returns the non-overlapping rectangles covering every
+// region claimed more than once
+func Overlaps(claims []image.Rectangle) []image.Rectangle {
+	var overlaps []image.Rectangle
+	for i, claim := range claims {
+		for _, c := range claims[:i] {
 			if c.Overlaps(claim) {
 				overlaps = Union(overlaps, c.Intersect(claim))
 			}
 		}
-		claims = append(claims, claim)
-	}
-	if err := sc.Err(); err != nil {
-		log.Fatal(err)
 	}
+	return overlaps
+}
+
+func PartOne(claims []image.Rectangle) int {
+	return Area(Overlaps(claims))
+}
 
-	// find intact ID
+// PartTwo returns the ID of the first claim which doesn't overlap any other
+func PartTwo(claims []image.Rectangle) (int, bool) {
+	overlaps := Overlaps(claims)
 	for i, c := range claims {
 		intact := true
 		for _, r := range overlaps {
@@ -182,9 +193,30 @@ func main() {
 			}
 		}
 		if intact {
-			fmt.Printf("Intact Claim: #%d\n", i+1)
+			return i + 1, true
 		}
 	}
+	return 0, false
+}
+
+type Solver struct{}
+
+func (Solver) Parse(r io.Reader) (advent.Input, error) {
+	return ParseInput(r)
+}
+
+func (Solver) PartOne(in advent.Input) (string, error) {
+	return strconv.Itoa(PartOne(in.([]image.Rectangle))), nil
+}
+
+func (Solver) PartTwo(in advent.Input) (string, error) {
+	id, ok := PartTwo(in.([]image.Rectangle))
+	if !ok {
+		return "", fmt.Errorf("no intact claim")
+	}
+	return strconv.Itoa(id), nil
+}
 
-	fmt.Printf("Overlap Area: %d\"\n", Area(overlaps))
+func init() {
+	advent.Register(3, Solver{})
 }
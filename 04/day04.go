@@ -1,15 +1,15 @@
-package main
+package day04
 
 import (
 	"bufio"
 	"fmt"
 	"io"
-	"log"
-	"os"
 	"regexp"
 	"sort"
 	"strconv"
 	"time"
+
+	"github.com/icholy/advent"
 )
 
 type RecordType string
@@ -230,22 +230,35 @@ func PartOne(gg []*Guard) int {
 	return worst.ID * min
 }
 
-func main() {
-	f, err := os.Open("input.txt")
-	if err != nil {
-		log.Fatal(err)
-	}
-	defer f.Close()
-	rr, err := ParseRecords(f)
+// ParseInput reads the records from r and replays them to find each guard's sleeps
+func ParseInput(r io.Reader) ([]*Guard, error) {
+	rr, err := ParseRecords(r)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 	t := NewTracker()
 	for _, r := range rr {
 		if err := t.Update(r); err != nil {
-			log.Fatal(err)
+			return nil, err
 		}
 	}
-	fmt.Printf("Answer (Part 1): %d\n", PartOne(t.Guards()))
-	fmt.Printf("Answer (Part 2): %d\n", PartTwo(t.Guards()))
+	return t.Guards(), nil
+}
+
+type Solver struct{}
+
+func (Solver) Parse(r io.Reader) (advent.Input, error) {
+	return ParseInput(r)
+}
+
+func (Solver) PartOne(in advent.Input) (string, error) {
+	return strconv.Itoa(PartOne(in.([]*Guard))), nil
+}
+
+func (Solver) PartTwo(in advent.Input) (string, error) {
+	return strconv.Itoa(PartTwo(in.([]*Guard))), nil
+}
+
+func init() {
+	advent.Register(4, Solver{})
 }
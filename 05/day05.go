@@ -1,10 +1,12 @@
-package main
+package day05
 
 import (
-	"fmt"
+	"io"
 	"io/ioutil"
-	"log"
+	"strconv"
 	"unicode"
+
+	"github.com/icholy/advent"
 )
 
 type RunePredicate func(rune) bool
@@ -95,12 +97,28 @@ func PartTwo(s string) int {
 	return min
 }
 
-func main() {
-	data, err := ioutil.ReadFile("input.txt")
+func ParseInput(r io.Reader) (string, error) {
+	data, err := ioutil.ReadAll(r)
 	if err != nil {
-		log.Fatal(err)
+		return "", err
 	}
-	polymers := string(data)
-	fmt.Printf("Answer (Part 1): %d\n", PartOne(polymers))
-	fmt.Printf("Answer (Part 2): %d\n", PartTwo(polymers))
+	return string(data), nil
+}
+
+type Solver struct{}
+
+func (Solver) Parse(r io.Reader) (advent.Input, error) {
+	return ParseInput(r)
+}
+
+func (Solver) PartOne(in advent.Input) (string, error) {
+	return strconv.Itoa(PartOne(in.(string))), nil
+}
+
+func (Solver) PartTwo(in advent.Input) (string, error) {
+	return strconv.Itoa(PartTwo(in.(string))), nil
+}
+
+func init() {
+	advent.Register(5, Solver{})
 }
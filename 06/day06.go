@@ -1,12 +1,14 @@
-package main
+package day06
 
 import (
 	"bufio"
 	"fmt"
 	"image"
-	"log"
+	"io"
 	"os"
+	"strconv"
 
+	"github.com/icholy/advent"
 	"github.com/icholy/draw"
 )
 
@@ -67,14 +69,9 @@ func IsFinite(point image.Point, points []image.Point) bool {
 	return false
 }
 
-func ReadInput(file string) ([]image.Point, error) {
+func ParseInput(r io.Reader) ([]image.Point, error) {
 	var pp []image.Point
-	f, err := os.Open(file)
-	if err != nil {
-		return nil, err
-	}
-	defer f.Close()
-	sc := bufio.NewScanner(f)
+	sc := bufio.NewScanner(r)
 	for sc.Scan() {
 		var p image.Point
 		_, err := fmt.Sscanf(sc.Text(), "%d, %d", &p.X, &p.Y)
@@ -182,14 +179,20 @@ func Draw(coords []image.Point) error {
 	return cv.WriteTo(os.Stdout)
 }
 
-func main() {
-	coords, err := ReadInput("input.txt")
-	if err != nil {
-		log.Fatal(err)
-	}
-	if err := Draw(coords); err != nil {
-		log.Fatal(err)
-	}
-	fmt.Printf("Answer (Part 1): %d\n", PartOne(coords))
-	fmt.Printf("Answer (Part 2): %d\n", PartTwo(coords))
+type Solver struct{}
+
+func (Solver) Parse(r io.Reader) (advent.Input, error) {
+	return ParseInput(r)
+}
+
+func (Solver) PartOne(in advent.Input) (string, error) {
+	return strconv.Itoa(PartOne(in.([]image.Point))), nil
+}
+
+func (Solver) PartTwo(in advent.Input) (string, error) {
+	return strconv.Itoa(PartTwo(in.([]image.Point))), nil
+}
+
+func init() {
+	advent.Register(6, Solver{})
 }
@@ -0,0 +1,153 @@
+package day07
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+)
+
+// Timing is when a step can run if there are enough workers to start
+// every step as soon as its dependencies are done
+type Timing struct {
+	Step           *Step
+	EarliestStart  time.Duration
+	EarliestFinish time.Duration
+	LatestStart    time.Duration
+	LatestFinish   time.Duration
+}
+
+// Slack is how long the step can be delayed without delaying the finish
+func (t *Timing) Slack() time.Duration {
+	return t.LatestStart - t.EarliestStart
+}
+
+// Analysis is the critical path analysis of a graph
+type Analysis struct {
+	// Timings has every step in the order they can start
+	Timings []*Timing
+
+	// Makespan is how long the steps take with unlimited workers.
+	// Adding workers stops helping once a schedule takes this long.
+	Makespan time.Duration
+
+	// Critical is the chain of steps without slack which decides the makespan
+	Critical []*Step
+}
+
+// Timing returns the timing of the named step
+func (a *Analysis) Timing(name string) (*Timing, bool) {
+	for _, t := range a.Timings {
+		if t.Step.Name == name {
+			return t, true
+		}
+	}
+	return nil, false
+}
+
+// Analyze works out the earliest and latest times every step can run.
+// The graph must be valid.
+func Analyze(g Graph, durations map[*Step]time.Duration) *Analysis {
+	var (
+		a       = &Analysis{}
+		timings = map[*Step]*Timing{}
+		next    = map[*Step][]*Step{}
+		order   []*Step
+		visit   func(s *Step)
+	)
+	// order the steps so they all come after their dependencies
+	visit = func(s *Step) {
+		if _, ok := timings[s]; ok {
+			return
+		}
+		timings[s] = &Timing{Step: s}
+		for _, d := range s.Deps {
+			visit(d)
+			next[d] = append(next[d], s)
+		}
+		order = append(order, s)
+	}
+	for _, s := range steps(g) {
+		visit(s)
+	}
+	for _, s := range order {
+		t := timings[s]
+		for _, d := range s.Deps {
+			if f := timings[d].EarliestFinish; f > t.EarliestStart {
+				t.EarliestStart = f
+			}
+		}
+		t.EarliestFinish = t.EarliestStart + durations[s]
+		if t.EarliestFinish > a.Makespan {
+			a.Makespan = t.EarliestFinish
+		}
+	}
+	for i := len(order) - 1; i >= 0; i-- {
+		t := timings[order[i]]
+		t.LatestFinish = a.Makespan
+		for _, n := range next[order[i]] {
+			if s := timings[n].LatestStart; s < t.LatestFinish {
+				t.LatestFinish = s
+			}
+		}
+		t.LatestStart = t.LatestFinish - durations[order[i]]
+	}
+	for _, s := range order {
+		a.Timings = append(a.Timings, timings[s])
+	}
+	sort.SliceStable(a.Timings, func(i, j int) bool {
+		return a.Timings[i].EarliestStart < a.Timings[j].EarliestStart
+	})
+	// walk back from the last step to finish through the dependencies
+	// which finished just in time, preferring names earlier in the alphabet
+	var critical *Timing
+	for _, t := range a.Timings {
+		if t.Slack() == 0 && t.EarliestFinish == a.Makespan && (critical == nil || t.Step.Name < critical.Step.Name) {
+			critical = t
+		}
+	}
+	for critical != nil {
+		a.Critical = append([]*Step{critical.Step}, a.Critical...)
+		var prev *Timing
+		for _, d := range critical.Step.Deps {
+			t := timings[d]
+			if t.Slack() == 0 && t.EarliestFinish == critical.EarliestStart && (prev == nil || d.Name < prev.Step.Name) {
+				prev = t
+			}
+		}
+		critical = prev
+	}
+	return a
+}
+
+// CriticalPath returns the longest chain of dependent steps, which is the
+// chain that decides how soon everything can finish. Ties are broken by name.
+func CriticalPath(g Graph, durations map[*Step]time.Duration) []*Step {
+	return Analyze(g, durations).Critical
+}
+
+// WriteAnalysis writes a table of each step's timings and how they
+// compare to the schedule, which can be nil
+func WriteAnalysis(w io.Writer, a *Analysis, sched *Schedule) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "step\tearliest start\tlatest start\tslack\tscheduled start\tworker")
+	for _, t := range a.Timings {
+		start, worker := "-", "-"
+		if sched != nil {
+			if as, ok := sched.Assignment(t.Step.Name); ok {
+				start, worker = as.Start.String(), fmt.Sprint(as.Worker)
+			}
+		}
+		fmt.Fprintf(tw, "%s\t%v\t%v\t%v\t%s\t%s\n", t.Step, t.EarliestStart, t.LatestStart, t.Slack(), start, worker)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "critical path: %v\n", a.Critical)
+	fmt.Fprintf(w, "makespan with unlimited workers: %v\n", a.Makespan)
+	if sched != nil {
+		fmt.Fprintf(w, "makespan with %d workers: %v\n", sched.Workers, sched.Total)
+	}
+	return nil
+}
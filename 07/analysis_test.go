@@ -0,0 +1,51 @@
+package day07
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestAnalyze(t *testing.T) {
+	input := readExample(t)
+	g, err := NewGraph(input.Constraints)
+	if err != nil {
+		t.Fatal(err)
+	}
+	durations, err := input.Config.Durations(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := Analyze(g, durations)
+	if got, want := fmt.Sprint(a.Critical), "[C F E]"; got != want {
+		t.Fatalf("got critical path %s, want %s", got, want)
+	}
+	if want := 14 * time.Second; a.Makespan != want {
+		t.Fatalf("got makespan %v, want %v", a.Makespan, want)
+	}
+	slack := map[string]time.Duration{
+		"A": time.Second,
+		"B": 3 * time.Second,
+		"C": 0,
+		"D": time.Second,
+		"E": 0,
+		"F": 0,
+	}
+	if len(a.Timings) != len(slack) {
+		t.Fatalf("got %d timings, want %d", len(a.Timings), len(slack))
+	}
+	for name, want := range slack {
+		tm, ok := a.Timing(name)
+		if !ok {
+			t.Fatalf("missing timing for %s", name)
+		}
+		if got := tm.Slack(); got != want {
+			t.Errorf("%s: got slack %v, want %v", name, got, want)
+		}
+	}
+	for i := 1; i < len(a.Timings); i++ {
+		if a.Timings[i].EarliestStart < a.Timings[i-1].EarliestStart {
+			t.Fatalf("%s starts before %s", a.Timings[i].Step, a.Timings[i-1].Step)
+		}
+	}
+}
@@ -0,0 +1,361 @@
+package day07
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/icholy/advent"
+)
+
+type Constraint struct {
+	Before, After string
+}
+
+func (c Constraint) String() string {
+	return fmt.Sprintf("%s before %s", c.Before, c.After)
+}
+
+type Input struct {
+	Constraints []Constraint
+	Config      Config
+}
+
+// ParseInput reads the constraints along with optional lines which
+// change how the steps are scheduled. For example:
+//
+//	-workers 2 -base 0s -priority critical
+//	duration compile 1m30s
+//	duration test 45s
+//
+// The priority is one of the names in Priorities. A step's duration line
+// takes precedence over the duration worked out from its name.
+func ParseInput(r io.Reader) (Input, error) {
+	var (
+		cc    []Constraint
+		args  []string
+		table = map[string]time.Duration{}
+		sc    = bufio.NewScanner(r)
+	)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "-") {
+			args = append(args, strings.Fields(line)...)
+			continue
+		}
+		if strings.HasPrefix(line, "duration ") {
+			name, d, err := parseDuration(strings.TrimPrefix(line, "duration "))
+			if err != nil {
+				return Input{}, err
+			}
+			if _, ok := table[name]; ok {
+				return Input{}, fmt.Errorf("duplicate duration for step %q", name)
+			}
+			table[name] = d
+			continue
+		}
+		var c Constraint
+		_, err := fmt.Sscanf(
+			line,
+			"Step %s must be finished before step %s can begin.",
+			&c.Before,
+			&c.After,
+		)
+		if err != nil {
+			return Input{}, err
+		}
+		cc = append(cc, c)
+	}
+	if err := sc.Err(); err != nil {
+		return Input{}, err
+	}
+	var (
+		fs       = flag.NewFlagSet("day07", flag.ContinueOnError)
+		workers  = fs.Int("workers", DefaultConfig.Workers, "number of workers")
+		base     = fs.Duration("base", DefaultConfig.Base, "time every step takes on top of its letter")
+		priority = fs.String("priority", "alphabetical", "order to start ready steps in")
+	)
+	fs.SetOutput(ioutil.Discard)
+	if err := fs.Parse(args); err != nil {
+		return Input{}, err
+	}
+	if fs.NArg() > 0 {
+		return Input{}, fmt.Errorf("unexpected argument: %q", fs.Arg(0))
+	}
+	p, err := ParsePriority(*priority)
+	if err != nil {
+		return Input{}, err
+	}
+	return Input{cc, Config{Workers: *workers, Base: *base, Table: table, Priority: p}}, nil
+}
+
+type State int
+
+const (
+	Todo State = iota
+	Working
+	Done
+)
+
+type Step struct {
+	Name  string
+	State State
+	Deps  []*Step
+}
+
+type ByName []*Step
+
+func (n ByName) Len() int           { return len(n) }
+func (n ByName) Swap(i, j int)      { n[i], n[j] = n[j], n[i] }
+func (n ByName) Less(i, j int) bool { return n[i].Name < n[j].Name }
+
+func (s Step) String() string { return s.Name }
+
+func (s Step) Ready() bool {
+	for _, d := range s.Deps {
+		if d.State != Done {
+			return false
+		}
+	}
+	return true
+}
+
+type Graph map[string]*Step
+
+func (g Graph) Step(name string) *Step {
+	if _, ok := g[name]; !ok {
+		g[name] = &Step{Name: name}
+	}
+	return g[name]
+}
+
+// Next returns the ready step with the lowest key from Priority.Keys,
+// or nil when there aren't any ready steps
+func (g Graph) Next(keys map[*Step]int64) *Step {
+	var next *Step
+	for _, s := range g {
+		if s.State == Todo && s.Ready() {
+			if next == nil || before(keys, s, next) {
+				next = s
+			}
+		}
+	}
+	return next
+}
+
+func (g Graph) Add(c Constraint) {
+	n := g.Step(c.After)
+	n.Deps = append(n.Deps, g.Step(c.Before))
+}
+
+// NewGraph builds a validated graph from the constraints
+func NewGraph(constraints []Constraint) (Graph, error) {
+	g := make(Graph)
+	for _, c := range constraints {
+		g.Add(c)
+	}
+	if err := g.Validate(); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// Validate checks that there are no cycles. A cycle is reported as the
+// chain of steps in the order they would have to run. Dependencies don't
+// need checking because Add creates the steps they refer to.
+func (g Graph) Validate() error {
+	var names []string
+	for name, s := range g {
+		if s.Name != name {
+			return fmt.Errorf("step %q is stored as %q", s.Name, name)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	var (
+		state = map[*Step]int{}
+		path  []*Step
+		visit func(s *Step) error
+	)
+	visit = func(s *Step) error {
+		switch state[s] {
+		case visited:
+			return nil
+		case visiting:
+			// the path runs from dependent to dependency, so the
+			// chain is reversed to put it in running order
+			var chain []string
+			for i := len(path) - 1; i >= 0; i-- {
+				chain = append(chain, path[i].Name)
+				if path[i] == s {
+					break
+				}
+			}
+			chain = append(chain, chain[0])
+			return fmt.Errorf("cycle: %s", strings.Join(chain, " -> "))
+		}
+		state[s] = visiting
+		path = append(path, s)
+		deps := append([]*Step{}, s.Deps...)
+		sort.Sort(ByName(deps))
+		for _, d := range deps {
+			if err := visit(d); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[s] = visited
+		return nil
+	}
+	for _, name := range names {
+		if err := visit(g[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PartOne returns the order a single worker does the steps in when
+// it starts them in the configured priority order
+func PartOne(input Input) (string, error) {
+	g, err := NewGraph(input.Constraints)
+	if err != nil {
+		return "", err
+	}
+	durations, err := input.Config.Durations(g)
+	if err != nil {
+		return "", err
+	}
+	var (
+		seq  strings.Builder
+		keys = input.Config.priority().Keys(g, durations)
+	)
+	for s := g.Next(keys); s != nil; s = g.Next(keys) {
+		s.State = Done
+		seq.WriteString(s.Name)
+	}
+	return seq.String(), nil
+}
+
+// PartTwo returns how long the configured workers take to finish every step
+func PartTwo(input Input) (time.Duration, error) {
+	g, err := NewGraph(input.Constraints)
+	if err != nil {
+		return 0, err
+	}
+	sched, err := Run(g, input.Config)
+	if err != nil {
+		return 0, err
+	}
+	return sched.Total, nil
+}
+
+type Solver struct{}
+
+func (Solver) Parse(r io.Reader) (advent.Input, error) {
+	input, err := ParseInput(r)
+	if err != nil {
+		return nil, err
+	}
+	g, err := NewGraph(input.Constraints)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := input.Config.Durations(g); err != nil {
+		return nil, err
+	}
+	return input, nil
+}
+
+// Configure sets one of the scheduling options:
+//
+//	workers=2
+//	base=0s
+//	priority=critical
+//	durations=steps.txt
+//
+// The durations file is read with ParseDurations and its steps take
+// precedence over the durations in the input.
+func (Solver) Configure(in advent.Input, name, value string) (advent.Input, error) {
+	input := in.(Input)
+	switch name {
+	case "workers":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("invalid number of workers: %q", value)
+		}
+		input.Config.Workers = n
+	case "base":
+		d, err := time.ParseDuration(value)
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("invalid duration: %q", value)
+		}
+		input.Config.Base = d
+	case "priority":
+		p, err := ParsePriority(value)
+		if err != nil {
+			return nil, err
+		}
+		input.Config.Priority = p
+	case "durations":
+		f, err := os.Open(value)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		durations, err := ParseDurations(f)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", value, err)
+		}
+		// the input's table is copied so the original isn't changed
+		table := map[string]time.Duration{}
+		for name, d := range input.Config.Table {
+			table[name] = d
+		}
+		for name, d := range durations {
+			table[name] = d
+		}
+		input.Config.Table = table
+		g, err := NewGraph(input.Constraints)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := input.Config.Durations(g); err != nil {
+			return nil, fmt.Errorf("%s: %v", value, err)
+		}
+	default:
+		return nil, fmt.Errorf("unknown option: %q", name)
+	}
+	return input, nil
+}
+
+func (Solver) PartOne(in advent.Input) (string, error) {
+	return PartOne(in.(Input))
+}
+
+func (Solver) PartTwo(in advent.Input) (string, error) {
+	d, err := PartTwo(in.(Input))
+	if err != nil {
+		return "", err
+	}
+	return d.String(), nil
+}
+
+func init() {
+	advent.Register(7, Solver{})
+}
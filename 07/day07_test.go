@@ -0,0 +1,91 @@
+package day07
+
+import (
+	"os"
+	"testing"
+)
+
+// constraints builds constraints from pairs of step names
+func constraints(pairs ...string) []Constraint {
+	var cc []Constraint
+	for i := 0; i+1 < len(pairs); i += 2 {
+		cc = append(cc, Constraint{Before: pairs[i], After: pairs[i+1]})
+	}
+	return cc
+}
+
+func readExample(t *testing.T) Input {
+	t.Helper()
+	f, err := os.Open("example.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	input, err := ParseInput(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return input
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		constraints []Constraint
+		err         string
+	}{
+		{
+			name:        "example",
+			constraints: constraints("C", "A", "C", "F", "A", "B", "A", "D", "B", "E", "D", "E", "F", "E"),
+		},
+		{
+			name:        "diamond",
+			constraints: constraints("A", "B", "A", "C", "B", "D", "C", "D"),
+		},
+		{
+			name:        "self loop",
+			constraints: constraints("A", "A"),
+			err:         "cycle: A -> A",
+		},
+		{
+			name:        "two steps",
+			constraints: constraints("A", "B", "B", "A"),
+			err:         "cycle: B -> A -> B",
+		},
+		{
+			name:        "three steps",
+			constraints: constraints("B", "C", "C", "A", "A", "B"),
+			err:         "cycle: B -> C -> A -> B",
+		},
+		{
+			name:        "cycle after a chain",
+			constraints: constraints("A", "B", "B", "C", "C", "D", "D", "C"),
+			err:         "cycle: D -> C -> D",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewGraph(tt.constraints)
+			if tt.err == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected error %q", tt.err)
+			}
+			if err.Error() != tt.err {
+				t.Fatalf("got error %q, want %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestValidateStoredName(t *testing.T) {
+	g := Graph{"A": &Step{Name: "B"}}
+	want := `step "B" is stored as "A"`
+	if err := g.Validate(); err == nil || err.Error() != want {
+		t.Fatalf("got error %v, want %q", err, want)
+	}
+}
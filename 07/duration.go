@@ -0,0 +1,150 @@
+package day07
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DurationFunc returns how long the named step takes.
+// It returns false when it doesn't know.
+type DurationFunc func(name string) (time.Duration, bool)
+
+// LetterDuration is the puzzle's duration model. Steps named with a
+// single letter take base plus a second for each letter of the alphabet
+// up to and including theirs, so A takes base+1s and Z takes base+26s.
+func LetterDuration(base time.Duration) DurationFunc {
+	return func(name string) (time.Duration, bool) {
+		if len(name) != 1 || name[0] < 'A' || name[0] > 'Z' {
+			return 0, false
+		}
+		return base + time.Duration(name[0]-'A'+1)*time.Second, true
+	}
+}
+
+// TableDuration looks steps up in table and uses fallback for the
+// steps which aren't in it. The fallback can be nil.
+func TableDuration(table map[string]time.Duration, fallback DurationFunc) DurationFunc {
+	return func(name string) (time.Duration, bool) {
+		if d, ok := table[name]; ok {
+			return d, true
+		}
+		if fallback != nil {
+			return fallback(name)
+		}
+		return 0, false
+	}
+}
+
+// ParseDurations reads a step name and duration from each line:
+//
+//	compile 1m30s
+//	test 45s
+//
+// Blank lines and lines starting with # are ignored.
+func ParseDurations(r io.Reader) (map[string]time.Duration, error) {
+	var (
+		table = map[string]time.Duration{}
+		sc    = bufio.NewScanner(r)
+		line  int
+	)
+	for sc.Scan() {
+		line++
+		text := strings.TrimSpace(sc.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		name, d, err := parseDuration(text)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+		if _, ok := table[name]; ok {
+			return nil, fmt.Errorf("line %d: duplicate step: %q", line, name)
+		}
+		table[name] = d
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return table, nil
+}
+
+// parseDuration parses a step name followed by its duration
+func parseDuration(text string) (string, time.Duration, error) {
+	fields := strings.Fields(text)
+	if len(fields) != 2 {
+		return "", 0, fmt.Errorf("expected a step name and duration, got %q", text)
+	}
+	d, err := time.ParseDuration(fields[1])
+	if err != nil || d < 0 {
+		return "", 0, fmt.Errorf("invalid duration: %q", fields[1])
+	}
+	return fields[0], d, nil
+}
+
+// Config is how the steps are scheduled
+type Config struct {
+	Workers int
+
+	// Duration is how long each step takes. It defaults to
+	// LetterDuration with Base when it's nil.
+	Duration DurationFunc
+	Base     time.Duration
+
+	// Table overrides Duration for the steps in it.
+	// Every step in it must be in the graph.
+	Table map[string]time.Duration
+
+	Priority Priority
+}
+
+// priority returns the configured priority, which defaults to Alphabetical
+func (c Config) priority() Priority {
+	if c.Priority == nil {
+		return Alphabetical{}
+	}
+	return c.Priority
+}
+
+// DefaultConfig is the puzzle's configuration
+var DefaultConfig = Config{
+	Workers: 5,
+	Base:    60 * time.Second,
+}
+
+// Durations returns the duration of every step in g
+func (c Config) Durations(g Graph) (map[*Step]time.Duration, error) {
+	var names []string
+	for name := range c.Table {
+		if _, ok := g[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	if len(names) > 0 {
+		sort.Strings(names)
+		return nil, fmt.Errorf("duration for unknown step %q", names[0])
+	}
+	duration := c.Duration
+	if duration == nil {
+		duration = LetterDuration(c.Base)
+	}
+	duration = TableDuration(c.Table, duration)
+	// the names are sorted so the same step is always reported
+	names = names[:0]
+	for name := range g {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	durations := map[*Step]time.Duration{}
+	for _, name := range names {
+		d, ok := duration(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown duration for step %q", name)
+		}
+		durations[g[name]] = d
+	}
+	return durations, nil
+}
@@ -0,0 +1,182 @@
+package day07
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/icholy/advent"
+)
+
+// edge is a dependency between two steps
+type edge struct {
+	Before, After *Step
+}
+
+// edges returns every dependency in g ordered by name
+func edges(g Graph) []edge {
+	var ee []edge
+	for _, s := range g {
+		for _, d := range s.Deps {
+			ee = append(ee, edge{d, s})
+		}
+	}
+	sort.Slice(ee, func(i, j int) bool {
+		if ee[i].Before.Name != ee[j].Before.Name {
+			return ee[i].Before.Name < ee[j].Before.Name
+		}
+		return ee[i].After.Name < ee[j].After.Name
+	})
+	return ee
+}
+
+// steps returns the steps in g ordered by name
+func steps(g Graph) []*Step {
+	var ss []*Step
+	for _, s := range g {
+		ss = append(ss, s)
+	}
+	sort.Sort(ByName(ss))
+	return ss
+}
+
+// onPath returns the steps and edges which are part of path
+func onPath(path []*Step) (map[*Step]bool, map[edge]bool) {
+	var (
+		nodes = map[*Step]bool{}
+		links = map[edge]bool{}
+	)
+	for i, s := range path {
+		nodes[s] = true
+		if i > 0 {
+			links[edge{path[i-1], s}] = true
+		}
+	}
+	return nodes, links
+}
+
+// labelLines returns a step's name followed by its assignment in sched
+func labelLines(s *Step, sched *Schedule) []string {
+	lines := []string{s.Name}
+	if sched != nil {
+		if a, ok := sched.Assignment(s.Name); ok {
+			lines = append(lines, fmt.Sprintf("worker %d", a.Worker), fmt.Sprintf("%v - %v", a.Start, a.End))
+		}
+	}
+	return lines
+}
+
+// WriteDOT writes g as a Graphviz digraph. Steps are labelled with their
+// worker, start and finish from sched, which can be nil, and the steps
+// and dependencies on the critical path are drawn in red.
+func WriteDOT(w io.Writer, g Graph, sched *Schedule, critical []*Step) error {
+	var (
+		bw           = bufio.NewWriter(w)
+		nodes, links = onPath(critical)
+		red          = ` color=red penwidth=2`
+	)
+	fmt.Fprintln(bw, "digraph steps {")
+	fmt.Fprintln(bw, "\trankdir=LR;")
+	for _, s := range steps(g) {
+		attrs := "label=" + strconv.Quote(strings.Join(labelLines(s, sched), "\n"))
+		if nodes[s] {
+			attrs += red
+		}
+		fmt.Fprintf(bw, "\t%s [%s];\n", strconv.Quote(s.Name), attrs)
+	}
+	for _, e := range edges(g) {
+		fmt.Fprintf(bw, "\t%s -> %s", strconv.Quote(e.Before.Name), strconv.Quote(e.After.Name))
+		if links[e] {
+			fmt.Fprintf(bw, " [%s]", strings.TrimSpace(red))
+		}
+		fmt.Fprintln(bw, ";")
+	}
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+// WriteMermaid writes g as a Mermaid flowchart with the same annotations
+// as WriteDOT. Step names are replaced by generated node IDs because
+// Mermaid only allows a few characters in them.
+func WriteMermaid(w io.Writer, g Graph, sched *Schedule, critical []*Step) error {
+	var (
+		bw           = bufio.NewWriter(w)
+		nodes, links = onPath(critical)
+		ids          = map[*Step]string{}
+		highlight    []string
+	)
+	fmt.Fprintln(bw, "graph LR")
+	for i, s := range steps(g) {
+		ids[s] = fmt.Sprintf("s%d", i)
+		label := strings.Join(labelLines(s, sched), "<br/>")
+		label = strings.Replace(label, `"`, "#quot;", -1)
+		fmt.Fprintf(bw, "\t%s[\"%s\"]\n", ids[s], label)
+		if nodes[s] {
+			highlight = append(highlight, ids[s])
+		}
+	}
+	var critEdges []string
+	for i, e := range edges(g) {
+		fmt.Fprintf(bw, "\t%s --> %s\n", ids[e.Before], ids[e.After])
+		if links[e] {
+			critEdges = append(critEdges, strconv.Itoa(i))
+		}
+	}
+	if len(highlight) > 0 {
+		fmt.Fprintln(bw, "\tclassDef critical stroke:#f00,stroke-width:3px")
+		fmt.Fprintf(bw, "\tclass %s critical\n", strings.Join(highlight, ","))
+	}
+	if len(critEdges) > 0 {
+		fmt.Fprintf(bw, "\tlinkStyle %s stroke:#f00,stroke-width:3px\n", strings.Join(critEdges, ","))
+	}
+	return bw.Flush()
+}
+
+// Export writes the puzzle's graph in the given format, annotated with
+// the schedule from PartTwo. The "analysis" format is a table of each
+// step's timings instead of a graph.
+func Export(w io.Writer, input Input, format string) error {
+	g, err := NewGraph(input.Constraints)
+	if err != nil {
+		return err
+	}
+	durations, err := input.Config.Durations(g)
+	if err != nil {
+		return err
+	}
+	sched, err := Run(g, input.Config)
+	if err != nil {
+		return err
+	}
+	analysis := Analyze(g, durations)
+	critical := analysis.Critical
+	switch format {
+	case "analysis":
+		if err := WriteAnalysis(w, analysis, sched); err != nil {
+			return err
+		}
+		makespans, err := ComparePriorities(g, input.Config)
+		if err != nil {
+			return err
+		}
+		for _, m := range makespans {
+			if _, err := fmt.Fprintf(w, "makespan with %d workers by %s priority: %v\n", input.Config.Workers, m.Priority, m.Total); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "dot":
+		return WriteDOT(w, g, sched, critical)
+	case "mermaid":
+		return WriteMermaid(w, g, sched, critical)
+	default:
+		return fmt.Errorf("unknown format: %q", format)
+	}
+}
+
+func (Solver) Export(in advent.Input, w io.Writer, format string) error {
+	return Export(w, in.(Input), format)
+}
@@ -0,0 +1,74 @@
+package day07
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExport(t *testing.T) {
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{
+			format: "dot",
+			want: `digraph steps {
+	rankdir=LR;
+	"A" [label="A\nworker 1\n3s - 4s"];
+	"B" [label="B\nworker 1\n4s - 6s"];
+	"C" [label="C\nworker 1\n0s - 3s" color=red penwidth=2];
+	"D" [label="D\nworker 1\n6s - 10s"];
+	"E" [label="E\nworker 1\n10s - 15s" color=red penwidth=2];
+	"F" [label="F\nworker 2\n3s - 9s" color=red penwidth=2];
+	"A" -> "B";
+	"A" -> "D";
+	"B" -> "E";
+	"C" -> "A";
+	"C" -> "F" [color=red penwidth=2];
+	"D" -> "E";
+	"F" -> "E" [color=red penwidth=2];
+}
+`,
+		},
+		{
+			format: "mermaid",
+			want: `graph LR
+	s0["A<br/>worker 1<br/>3s - 4s"]
+	s1["B<br/>worker 1<br/>4s - 6s"]
+	s2["C<br/>worker 1<br/>0s - 3s"]
+	s3["D<br/>worker 1<br/>6s - 10s"]
+	s4["E<br/>worker 1<br/>10s - 15s"]
+	s5["F<br/>worker 2<br/>3s - 9s"]
+	s0 --> s1
+	s0 --> s3
+	s1 --> s4
+	s2 --> s0
+	s2 --> s5
+	s3 --> s4
+	s5 --> s4
+	classDef critical stroke:#f00,stroke-width:3px
+	class s2,s4,s5 critical
+	linkStyle 4,6 stroke:#f00,stroke-width:3px
+`,
+		},
+	}
+	input := readExample(t)
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var b strings.Builder
+			if err := Export(&b, input, tt.format); err != nil {
+				t.Fatal(err)
+			}
+			if got := b.String(); got != tt.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExportUnknownFormat(t *testing.T) {
+	want := `unknown format: "svg"`
+	if err := Export(&strings.Builder{}, readExample(t), "svg"); err == nil || err.Error() != want {
+		t.Fatalf("got error %v, want %q", err, want)
+	}
+}
@@ -0,0 +1,147 @@
+package day07
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Priority decides which ready steps are started first
+type Priority interface {
+	// Keys returns a sort key for every step in g. Ready steps with
+	// lower keys start first and ties start in alphabetical order.
+	Keys(g Graph, durations map[*Step]time.Duration) map[*Step]int64
+}
+
+// Alphabetical is the puzzle's priority
+type Alphabetical struct{}
+
+func (Alphabetical) Keys(g Graph, _ map[*Step]time.Duration) map[*Step]int64 {
+	return map[*Step]int64{}
+}
+
+// LongestFirst starts the steps which take the longest first
+type LongestFirst struct{}
+
+func (LongestFirst) Keys(g Graph, durations map[*Step]time.Duration) map[*Step]int64 {
+	keys := map[*Step]int64{}
+	for _, s := range g {
+		keys[s] = -int64(durations[s])
+	}
+	return keys
+}
+
+// MostDependents starts the steps which the most other steps depend on,
+// directly or indirectly, first
+type MostDependents struct{}
+
+func (MostDependents) Keys(g Graph, _ map[*Step]time.Duration) map[*Step]int64 {
+	next := map[*Step][]*Step{}
+	for _, s := range g {
+		for _, d := range s.Deps {
+			next[d] = append(next[d], s)
+		}
+	}
+	keys := map[*Step]int64{}
+	for _, s := range g {
+		var (
+			seen  = map[*Step]bool{}
+			stack = append([]*Step{}, next[s]...)
+		)
+		for len(stack) > 0 {
+			n := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if seen[n] {
+				continue
+			}
+			seen[n] = true
+			stack = append(stack, next[n]...)
+		}
+		keys[s] = -int64(len(seen))
+	}
+	return keys
+}
+
+// CriticalPathFirst starts the steps with the earliest latest start
+// first, so the steps on the critical path are never left waiting
+type CriticalPathFirst struct{}
+
+func (CriticalPathFirst) Keys(g Graph, durations map[*Step]time.Duration) map[*Step]int64 {
+	keys := map[*Step]int64{}
+	for _, t := range Analyze(g, durations).Timings {
+		keys[t.Step] = int64(t.LatestStart)
+	}
+	return keys
+}
+
+// Priorities are the available priorities by name
+var Priorities = map[string]Priority{
+	"alphabetical": Alphabetical{},
+	"longest":      LongestFirst{},
+	"dependents":   MostDependents{},
+	"critical":     CriticalPathFirst{},
+}
+
+// PriorityNames returns the names of the available priorities in order
+func PriorityNames() []string {
+	var names []string
+	for name := range Priorities {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ParsePriority looks up a priority by name
+func ParsePriority(name string) (Priority, error) {
+	p, ok := Priorities[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown priority: %q", name)
+	}
+	return p, nil
+}
+
+// Makespan is how long a schedule using a priority takes
+type Makespan struct {
+	Priority string
+	Total    time.Duration
+}
+
+// ComparePriorities schedules g with every available priority
+func ComparePriorities(g Graph, config Config) ([]Makespan, error) {
+	var mm []Makespan
+	for _, name := range PriorityNames() {
+		config.Priority = Priorities[name]
+		sched, err := Run(g, config)
+		if err != nil {
+			return nil, err
+		}
+		mm = append(mm, Makespan{name, sched.Total})
+	}
+	return mm, nil
+}
+
+// before reports whether a starts before b when they're ordered
+// by their keys and then their names
+func before(keys map[*Step]int64, a, b *Step) bool {
+	if keys[a] != keys[b] {
+		return keys[a] < keys[b]
+	}
+	return a.Name < b.Name
+}
+
+// byPriority is a heap of steps ordered by their keys and then their names
+type byPriority struct {
+	steps []*Step
+	keys  map[*Step]int64
+}
+
+func (h *byPriority) Len() int           { return len(h.steps) }
+func (h *byPriority) Less(i, j int) bool { return before(h.keys, h.steps[i], h.steps[j]) }
+func (h *byPriority) Swap(i, j int)      { h.steps[i], h.steps[j] = h.steps[j], h.steps[i] }
+func (h *byPriority) Push(x interface{}) { h.steps = append(h.steps, x.(*Step)) }
+func (h *byPriority) Pop() interface{} {
+	s := h.steps[len(h.steps)-1]
+	h.steps = h.steps[:len(h.steps)-1]
+	return s
+}
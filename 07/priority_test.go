@@ -0,0 +1,90 @@
+package day07
+
+import (
+	"testing"
+	"time"
+)
+
+// priorityInput is a graph where each priority starts a different step
+// first. D takes the longest, C has the most dependents, and B leads to
+// G which is on the critical path.
+func priorityInput() Input {
+	return Input{
+		Constraints: constraints("A", "H", "D", "H", "C", "E", "C", "F", "B", "G"),
+		Config: Config{
+			Workers: 2,
+			Duration: TableDuration(map[string]time.Duration{
+				"A": time.Second,
+				"B": time.Second,
+				"C": time.Second,
+				"D": 5 * time.Second,
+				"E": time.Second,
+				"F": time.Second,
+				"G": 10 * time.Second,
+				"H": time.Second,
+			}, nil),
+		},
+	}
+}
+
+func TestPriorities(t *testing.T) {
+	tests := []struct {
+		priority string
+		order    string
+	}{
+		{priority: "alphabetical", order: "ABCDEFGH"},
+		{priority: "longest", order: "DABGCEFH"},
+		{priority: "dependents", order: "CABDEFGH"},
+		{priority: "critical", order: "BGDACEFH"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.priority, func(t *testing.T) {
+			p, err := ParsePriority(tt.priority)
+			if err != nil {
+				t.Fatal(err)
+			}
+			input := priorityInput()
+			input.Config.Priority = p
+			order, err := PartOne(input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if order != tt.order {
+				t.Fatalf("got %s, want %s", order, tt.order)
+			}
+		})
+	}
+}
+
+func TestComparePriorities(t *testing.T) {
+	input := priorityInput()
+	g, err := NewGraph(input.Constraints)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ComparePriorities(g, input.Config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Makespan{
+		{"alphabetical", 14 * time.Second},
+		{"critical", 11 * time.Second},
+		{"dependents", 14 * time.Second},
+		{"longest", 12 * time.Second},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d makespans, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("got %v, want %v", got[i], want[i])
+		}
+	}
+}
+
+func TestParsePriority(t *testing.T) {
+	want := `unknown priority: "fastest"`
+	if _, err := ParsePriority("fastest"); err == nil || err.Error() != want {
+		t.Fatalf("got error %v, want %q", err, want)
+	}
+}
@@ -0,0 +1,172 @@
+package day07
+
+import (
+	"container/heap"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Worker works on one step at a time
+type Worker struct {
+	ID   int
+	Step *Step
+	End  time.Duration
+}
+
+func (w *Worker) Idle() bool {
+	return w.Step == nil
+}
+
+func (w Worker) String() string {
+	if w.Idle() {
+		return "idle"
+	}
+	return w.Step.Name
+}
+
+type Workers []*Worker
+
+func NewWorkers(n int) Workers {
+	var ww Workers
+	for i := 0; i < n; i++ {
+		ww = append(ww, &Worker{ID: i + 1})
+	}
+	return ww
+}
+
+func (ww Workers) String() string {
+	var b strings.Builder
+	for _, w := range ww {
+		fmt.Fprintf(&b, "worker_%d=%s ", w.ID, w)
+	}
+	return b.String()
+}
+
+// Assignment is a worker working on a step from Start until End
+type Assignment struct {
+	Worker     int
+	Step       *Step
+	Start, End time.Duration
+}
+
+func (a Assignment) String() string {
+	return fmt.Sprintf("worker_%d %s %v-%v", a.Worker, a.Step, a.Start, a.End)
+}
+
+// Schedule is every assignment in the order they started,
+// along with the time it takes for all of them to finish
+type Schedule struct {
+	Workers     int
+	Assignments []Assignment
+	Total       time.Duration
+}
+
+// Assignment returns the assignment of the named step
+func (s *Schedule) Assignment(name string) (Assignment, bool) {
+	for _, a := range s.Assignments {
+		if a.Step.Name == name {
+			return a, true
+		}
+	}
+	return Assignment{}, false
+}
+
+// Run schedules the steps in g on the configured workers. Rather than
+// ticking through every second, it jumps from one step finishing to the
+// next, so the work done only depends on the number of steps. Ready steps
+// are started in the configured priority order by the lowest numbered idle
+// worker.
+func Run(g Graph, config Config) (*Schedule, error) {
+	if config.Workers < 1 {
+		return nil, fmt.Errorf("invalid number of workers: %d", config.Workers)
+	}
+	if err := g.Validate(); err != nil {
+		return nil, err
+	}
+	durations, err := config.Durations(g)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		sched   = &Schedule{Workers: config.Workers}
+		workers = NewWorkers(config.Workers)
+		idle    = append(Workers{}, workers...)
+		busy    = &byEnd{}
+		ready   = &byPriority{keys: config.priority().Keys(g, durations)}
+		waiting = map[*Step]int{}
+		next    = map[*Step][]*Step{}
+		now     time.Duration
+		done    int
+	)
+	for _, s := range g {
+		s.State = Todo
+		waiting[s] = len(s.Deps)
+		for _, d := range s.Deps {
+			next[d] = append(next[d], s)
+		}
+		if len(s.Deps) == 0 {
+			heap.Push(ready, s)
+		}
+	}
+	for {
+		for len(idle) > 0 && ready.Len() > 0 {
+			var (
+				s = heap.Pop(ready).(*Step)
+				w = idle[0]
+			)
+			idle = idle[1:]
+			s.State = Working
+			w.Step = s
+			w.End = now + durations[s]
+			heap.Push(busy, w)
+			sched.Assignments = append(sched.Assignments, Assignment{w.ID, s, now, w.End})
+		}
+		if busy.Len() == 0 {
+			break
+		}
+		// finish every step which ends at the same time before starting
+		// any more, so the ready steps are all considered together
+		now = (*busy)[0].End
+		for busy.Len() > 0 && (*busy)[0].End == now {
+			w := heap.Pop(busy).(*Worker)
+			w.Step.State = Done
+			done++
+			for _, s := range next[w.Step] {
+				if waiting[s]--; waiting[s] == 0 {
+					heap.Push(ready, s)
+				}
+			}
+			w.Step = nil
+			idle = append(idle, w)
+		}
+		sort.Slice(idle, func(i, j int) bool {
+			return idle[i].ID < idle[j].ID
+		})
+	}
+	if done != len(g) {
+		return nil, fmt.Errorf("%d of %d steps can never start", len(g)-done, len(g))
+	}
+	sched.Total = now
+	return sched, nil
+}
+
+// byEnd is a heap of busy workers ordered by when they finish
+type byEnd []*Worker
+
+func (h byEnd) Len() int { return len(h) }
+func (h byEnd) Less(i, j int) bool {
+	if h[i].End != h[j].End {
+		return h[i].End < h[j].End
+	}
+	return h[i].ID < h[j].ID
+}
+func (h byEnd) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *byEnd) Push(x interface{}) { *h = append(*h, x.(*Worker)) }
+func (h *byEnd) Pop() interface{} {
+	old := *h
+	w := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return w
+}
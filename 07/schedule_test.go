@@ -0,0 +1,45 @@
+package day07
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name    string
+		workers int
+		total   time.Duration
+		err     string
+	}{
+		{name: "one worker", workers: 1, total: 21 * time.Second},
+		{name: "two workers", workers: 2, total: 15 * time.Second},
+		{name: "unlimited workers", workers: 6, total: 14 * time.Second},
+		{name: "no workers", workers: 0, err: "invalid number of workers: 0"},
+		{name: "negative workers", workers: -1, err: "invalid number of workers: -1"},
+	}
+	input := readExample(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := NewGraph(input.Constraints)
+			if err != nil {
+				t.Fatal(err)
+			}
+			config := input.Config
+			config.Workers = tt.workers
+			sched, err := Run(g, config)
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("got error %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if sched.Total != tt.total {
+				t.Fatalf("got %v, want %v", sched.Total, tt.total)
+			}
+		})
+	}
+}
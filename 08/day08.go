@@ -1,12 +1,13 @@
-package main
+package day08
 
 import (
 	"fmt"
 	"io"
 	"io/ioutil"
-	"log"
 	"strconv"
 	"strings"
+
+	"github.com/icholy/advent"
 )
 
 type Node struct {
@@ -96,8 +97,8 @@ func (p *Parser) Root() *Node {
 	return node
 }
 
-func ReadInput(file string) ([]int, error) {
-	data, err := ioutil.ReadFile(file)
+func ParseInput(r io.Reader) ([]int, error) {
+	data, err := ioutil.ReadAll(r)
 	if err != nil {
 		return nil, err
 	}
@@ -139,17 +140,34 @@ func PartTwo(n *Node) int {
 	return sum
 }
 
-func main() {
-	nums, err := ReadInput("input.txt")
+// ParseTree reads the numbers from r and parses them into a tree
+func ParseTree(r io.Reader) (*Node, error) {
+	nums, err := ParseInput(r)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 	parser := NewParser(nums)
 	root := parser.Root()
 	if err := parser.Err(); err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
-	fmt.Println(root.Debug())
-	fmt.Printf("Answer (Part 1): %d\n", PartOne(root))
-	fmt.Printf("Answer (Part 2): %d\n", PartTwo(root))
+	return root, nil
+}
+
+type Solver struct{}
+
+func (Solver) Parse(r io.Reader) (advent.Input, error) {
+	return ParseTree(r)
+}
+
+func (Solver) PartOne(in advent.Input) (string, error) {
+	return strconv.Itoa(PartOne(in.(*Node))), nil
+}
+
+func (Solver) PartTwo(in advent.Input) (string, error) {
+	return strconv.Itoa(PartTwo(in.(*Node))), nil
+}
+
+func init() {
+	advent.Register(8, Solver{})
 }
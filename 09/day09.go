@@ -1,10 +1,12 @@
-package main
+package day09
 
 import (
 	"fmt"
-	"log"
-	"os"
+	"io"
+	"strconv"
 	"strings"
+
+	"github.com/icholy/advent"
 )
 
 type Input struct {
@@ -12,14 +14,9 @@ type Input struct {
 	NumMarbles int
 }
 
-func ReadInput(file string) (Input, error) {
+func ParseInput(r io.Reader) (Input, error) {
 	var input Input
-	f, err := os.Open(file)
-	if err != nil {
-		return input, err
-	}
-	defer f.Close()
-	if _, err := fmt.Fscanf(f,
+	if _, err := fmt.Fscanf(r,
 		"%d players; last marble is worth %d points",
 		&input.NumPlayers,
 		&input.NumMarbles,
@@ -161,11 +158,20 @@ func PartTwo(input Input) int {
 	return max
 }
 
-func main() {
-	input, err := ReadInput("input.txt")
-	if err != nil {
-		log.Fatal(err)
-	}
-	fmt.Printf("Answer (Part 1): %d\n", PartOne(input))
-	fmt.Printf("Answer (Part 2): %d\n", PartTwo(input))
+type Solver struct{}
+
+func (Solver) Parse(r io.Reader) (advent.Input, error) {
+	return ParseInput(r)
+}
+
+func (Solver) PartOne(in advent.Input) (string, error) {
+	return strconv.Itoa(PartOne(in.(Input))), nil
+}
+
+func (Solver) PartTwo(in advent.Input) (string, error) {
+	return strconv.Itoa(PartTwo(in.(Input))), nil
+}
+
+func init() {
+	advent.Register(9, Solver{})
 }
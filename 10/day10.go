@@ -1,12 +1,14 @@
-package main
+package day10
 
 import (
 	"bufio"
 	"fmt"
 	"image"
-	"log"
-	"os"
+	"io"
+	"strconv"
+	"strings"
 
+	"github.com/icholy/advent"
 	"github.com/icholy/draw"
 	"github.com/spakin/disjoint"
 )
@@ -36,14 +38,9 @@ func (l Light) String() string {
 	return fmt.Sprintf("pos=%s vel=%s", l.Pos, l.Vel)
 }
 
-func ReadInput(file string) ([]Light, error) {
+func ParseInput(r io.Reader) ([]Light, error) {
 	var lights []Light
-	f, err := os.Open(file)
-	if err != nil {
-		return nil, err
-	}
-	defer f.Close()
-	sc := bufio.NewScanner(f)
+	sc := bufio.NewScanner(r)
 	for sc.Scan() {
 		var l Light
 		_, err := fmt.Sscanf(sc.Text(),
@@ -119,7 +116,7 @@ func Simulate(lights []Light, seconds int) []Light {
 	return simulated
 }
 
-func Draw(lights []Light) error {
+func Draw(w io.Writer, lights []Light) error {
 	cv := draw.NewCanvas(300, 300)
 	cv.Draw(cv.Bounds().Fill(), '.')
 	for i, g := range Groups(lights) {
@@ -127,13 +124,29 @@ func Draw(lights []Light) error {
 			cv.Draw(draw.FromImagePoint(l.Pos), 'A'+byte(i))
 		}
 	}
-	return cv.WriteTo(os.Stdout)
+	return cv.WriteTo(w)
+}
+
+type Solver struct{}
+
+func (Solver) Parse(r io.Reader) (advent.Input, error) {
+	return ParseInput(r)
 }
 
-func main() {
-	lights, err := ReadInput("input.txt")
-	if err != nil {
-		log.Fatal(err)
+// PartOne draws the lights at the moment they spell out the message
+func (Solver) PartOne(in advent.Input) (string, error) {
+	lights := in.([]Light)
+	var b strings.Builder
+	if err := Draw(&b, Simulate(lights, Search(lights))); err != nil {
+		return "", err
 	}
-	Draw(Simulate(lights, Search(lights)))
+	return b.String(), nil
+}
+
+func (Solver) PartTwo(in advent.Input) (string, error) {
+	return strconv.Itoa(Search(in.([]Light))), nil
+}
+
+func init() {
+	advent.Register(10, Solver{})
 }
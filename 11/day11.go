@@ -0,0 +1,256 @@
+package day11
+
+import (
+	"flag"
+	"fmt"
+	"image"
+	"io"
+	"io/ioutil"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/icholy/advent"
+)
+
+type Solver struct{}
+
+func (Solver) Parse(r io.Reader) (advent.Input, error) {
+	return ParseInput(r)
+}
+
+func (Solver) PartOne(in advent.Input) (string, error) {
+	return PartOne(in.(Input)).String(), nil
+}
+
+func (Solver) PartTwo(in advent.Input) (string, error) {
+	return PartTwo(in.(Input)).String(), nil
+}
+
+func init() {
+	advent.Register(11, Solver{})
+}
+
+// Grid is the area covered by the fuel cells in the puzzle
+var Grid = image.Rect(1, 1, 300, 300)
+
+type Input struct {
+	Serial int
+	Grid   image.Rectangle
+
+	// MinSize and MaxSize are the range of kernel sizes searched by PartTwo
+	MinSize, MaxSize int
+}
+
+// ParseInput reads the grid serial number followed by optional flags
+// which change the grid and kernel sizes. For example:
+//
+//	7672 -grid 300x300 -min 1 -max 300
+//
+// The grid's cells are numbered from 1 and the sizes default to
+// covering every kernel that fits.
+func ParseInput(r io.Reader) (Input, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return Input{}, err
+	}
+	args := strings.Fields(string(data))
+	if len(args) == 0 {
+		return Input{}, fmt.Errorf("missing serial number")
+	}
+	serial, err := strconv.Atoi(args[0])
+	if err != nil {
+		return Input{}, fmt.Errorf("invalid serial number: %q", args[0])
+	}
+	var (
+		input = Input{Serial: serial}
+		fs    = flag.NewFlagSet("day11", flag.ContinueOnError)
+		size  = fs.String("grid", fmt.Sprintf("%dx%d", Grid.Dx()+1, Grid.Dy()+1), "grid size")
+	)
+	fs.SetOutput(ioutil.Discard)
+	fs.IntVar(&input.MinSize, "min", 1, "smallest kernel size")
+	fs.IntVar(&input.MaxSize, "max", 0, "largest kernel size, 0 means the grid size")
+	if err := fs.Parse(args[1:]); err != nil {
+		return Input{}, err
+	}
+	w, h, err := parseSize(*size)
+	if err != nil {
+		return Input{}, err
+	}
+	input.Grid = image.Rect(1, 1, w, h)
+	if input.MaxSize == 0 {
+		input.MaxSize = w
+		if h < w {
+			input.MaxSize = h
+		}
+	}
+	if err := input.Validate(); err != nil {
+		return Input{}, err
+	}
+	return input, nil
+}
+
+// parseSize parses a grid size such as 300x300
+func parseSize(s string) (w, h int, err error) {
+	invalid := fmt.Errorf("invalid grid size: %q", s)
+	wh := strings.Split(s, "x")
+	if len(wh) != 2 {
+		return 0, 0, invalid
+	}
+	if w, err = strconv.Atoi(wh[0]); err != nil || w < 1 {
+		return 0, 0, invalid
+	}
+	if h, err = strconv.Atoi(wh[1]); err != nil || h < 1 {
+		return 0, 0, invalid
+	}
+	return w, h, nil
+}
+
+// Validate checks that every kernel size fits in the grid
+// and that the grid is big enough for PartOne's 3x3 kernels
+func (in Input) Validate() error {
+	w, h := in.Grid.Dx()+1, in.Grid.Dy()+1
+	if w < 3 || h < 3 {
+		return fmt.Errorf("a %dx%d grid is smaller than 3x3", w, h)
+	}
+	if in.MinSize < 1 || in.MinSize > in.MaxSize {
+		return fmt.Errorf("invalid kernel sizes: %d-%d", in.MinSize, in.MaxSize)
+	}
+	if in.MaxSize > w || in.MaxSize > h {
+		return fmt.Errorf("kernel size %d doesn't fit in a %dx%d grid", in.MaxSize, w, h)
+	}
+	return nil
+}
+
+func PartOne(input Input) image.Point {
+	var (
+		grid      = NewPowerGrid(input.Serial, input.Grid)
+		bestpower int
+		bestcell  image.Point
+		found     bool
+	)
+	ForEachKernel(3, grid.Bounds, func(kernel image.Rectangle) {
+		if power := grid.Sum(kernel); !found || power > bestpower {
+			found = true
+			bestpower = power
+			bestcell = kernel.Min
+		}
+	})
+	return bestcell
+}
+
+// PartTwo searches every kernel size between input.MinSize and input.MaxSize.
+// The sizes are handed out to one worker per CPU.
+func PartTwo(input Input) PartTwoResult {
+	var (
+		grid    = NewPowerGrid(input.Serial, input.Grid)
+		sizes   = make(chan int)
+		result  = make(chan PartTwoResult)
+		workers = runtime.NumCPU()
+		best    PartTwoResult
+	)
+
+	for i := 0; i < workers; i++ {
+		go PartTwoWorker(grid, sizes, result)
+	}
+	go func() {
+		for size := input.MinSize; size <= input.MaxSize; size++ {
+			sizes <- size
+		}
+		close(sizes)
+	}()
+
+	for i := 0; i < workers; i++ {
+		if res := <-result; res.Better(best) {
+			best = res
+		}
+	}
+
+	return best
+}
+
+type PartTwoResult struct {
+	Point image.Point
+	Power int
+	Size  int
+}
+
+func (r PartTwoResult) String() string {
+	return fmt.Sprintf("%s, %d", r.Point, r.Size)
+}
+
+// Better reports whether r has more power than other. Ties go to
+// the smaller kernel so the result doesn't depend on which worker
+// finishes first. A result without a size, which is what a worker
+// that didn't get any sizes sends, is never better.
+func (r PartTwoResult) Better(other PartTwoResult) bool {
+	switch {
+	case r.Size == 0:
+		return false
+	case other.Size == 0:
+		return true
+	case r.Power != other.Power:
+		return r.Power > other.Power
+	default:
+		return r.Size < other.Size
+	}
+}
+
+// PartTwoWorker finds the best kernel of every size received from sizes.
+// It sends its best result to out once sizes is closed.
+func PartTwoWorker(grid *PowerGrid, sizes <-chan int, out chan<- PartTwoResult) {
+	var best PartTwoResult
+	for size := range sizes {
+		ForEachKernel(size, grid.Bounds, func(kernel image.Rectangle) {
+			if power := grid.Sum(kernel); best.Size == 0 || power > best.Power {
+				best = PartTwoResult{kernel.Min, power, size}
+			}
+		})
+	}
+	out <- best
+}
+
+func ForEachKernel(size int, bounds image.Rectangle, f func(r image.Rectangle)) {
+	kernel := image.Rect(0, 0, size-1, size-1).Add(bounds.Min)
+	if kernel.Max.X > bounds.Max.X {
+		return
+	}
+	for kernel.Max.Y <= bounds.Max.Y {
+		f(kernel)
+		kernel = kernel.Add(image.Pt(1, 0))
+		if kernel.Max.X > bounds.Max.X {
+			dx := kernel.Min.X - bounds.Min.X
+			kernel = kernel.Add(image.Pt(-dx, 1))
+		}
+	}
+}
+
+func ForEachCell(r image.Rectangle, f func(p image.Point)) {
+	for x := r.Min.X; x <= r.Max.X; x++ {
+		for y := r.Min.Y; y <= r.Max.Y; y++ {
+			f(image.Pt(x, y))
+		}
+	}
+}
+
+func Power(cell image.Point, serial int) int {
+	// Find the fuel cell's rack ID, which is its X coordinate plus 10.
+	rackID := cell.X + 10
+
+	// Begin with a power level of the rack ID times the Y coordinate.
+	power := rackID * cell.Y
+
+	// Increase the power level by the value of the grid serial number (your puzzle input)
+	power += serial
+
+	// Set the power level to itself multiplied by the rack ID.
+	power *= rackID
+
+	// Keep only the hundreds digit of the power level (so 12345 becomes 3; numbers with no hundreds digit become 0).
+	power = (power / 100) % 10
+
+	// Subtract 5 from the power level.
+	power -= 5
+
+	return power
+}
@@ -0,0 +1,56 @@
+package day11
+
+import (
+	"image"
+	"strings"
+	"testing"
+)
+
+func TestParseInput(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Input
+	}{
+		{"18", Input{Serial: 18, Grid: Grid, MinSize: 1, MaxSize: 300}},
+		{"18 -grid 10x20", Input{Serial: 18, Grid: image.Rect(1, 1, 10, 20), MinSize: 1, MaxSize: 10}},
+		{"18 -grid 3x3 -min 2 -max 3", Input{Serial: 18, Grid: image.Rect(1, 1, 3, 3), MinSize: 2, MaxSize: 3}},
+	}
+	for _, tt := range tests {
+		got, err := ParseInput(strings.NewReader(tt.input))
+		if err != nil {
+			t.Errorf("%q: %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%q: got %+v, want %+v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestParseInputErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		err   string
+	}{
+		{"", "missing serial number"},
+		{"abc", `invalid serial number: "abc"`},
+		{"18 -grid 300x300junk", `invalid grid size: "300x300junk"`},
+		{"18 -grid 300", `invalid grid size: "300"`},
+		{"18 -grid 0x300", `invalid grid size: "0x300"`},
+		{"18 -grid 2x300", "a 2x300 grid is smaller than 3x3"},
+		{"18 -grid 300x1", "a 300x1 grid is smaller than 3x3"},
+		{"18 -grid 10x20 -max 11", "kernel size 11 doesn't fit in a 10x20 grid"},
+		{"18 -min 5 -max 4", "invalid kernel sizes: 5-4"},
+		{"18 -min 0", "invalid kernel sizes: 0-300"},
+	}
+	for _, tt := range tests {
+		_, err := ParseInput(strings.NewReader(tt.input))
+		if err == nil {
+			t.Errorf("%q: expected error %q", tt.input, tt.err)
+			continue
+		}
+		if err.Error() != tt.err {
+			t.Errorf("%q: got error %q, want %q", tt.input, err, tt.err)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package day11
+
+import "image"
+
+// PowerGrid is a summed-area table of the fuel cell power levels.
+// Each entry holds the total power of every cell above and to the left
+// of it, which lets the power of any rectangle be found with 4 lookups.
+//
+// Like the rest of this package, rectangles include their Max edge.
+type PowerGrid struct {
+	Bounds image.Rectangle
+	stride int
+	sums   []int
+}
+
+// NewPowerGrid computes the power levels of every cell in bounds
+func NewPowerGrid(serial int, bounds image.Rectangle) *PowerGrid {
+	// the table has an extra row and column of zeros at the top and left
+	// so the cells along the edges don't need special cases.
+	w, h := bounds.Dx()+1, bounds.Dy()+1
+	g := &PowerGrid{
+		Bounds: bounds,
+		stride: w + 1,
+		sums:   make([]int, (w+1)*(h+1)),
+	}
+	for y := 1; y <= h; y++ {
+		for x := 1; x <= w; x++ {
+			cell := bounds.Min.Add(image.Pt(x-1, y-1))
+			g.sums[g.index(x, y)] = Power(cell, serial) +
+				g.sums[g.index(x-1, y)] +
+				g.sums[g.index(x, y-1)] -
+				g.sums[g.index(x-1, y-1)]
+		}
+	}
+	return g
+}
+
+func (g *PowerGrid) index(x, y int) int {
+	return y*g.stride + x
+}
+
+// Sum returns the total power of the cells in r.
+// It panics if r isn't inside the grid's bounds.
+func (g *PowerGrid) Sum(r image.Rectangle) int {
+	if !g.contains(r) {
+		panic("day11: rectangle outside of the power grid")
+	}
+	var (
+		x0 = r.Min.X - g.Bounds.Min.X
+		y0 = r.Min.Y - g.Bounds.Min.Y
+		x1 = r.Max.X - g.Bounds.Min.X + 1
+		y1 = r.Max.Y - g.Bounds.Min.Y + 1
+	)
+	return g.sums[g.index(x1, y1)] -
+		g.sums[g.index(x0, y1)] -
+		g.sums[g.index(x1, y0)] +
+		g.sums[g.index(x0, y0)]
+}
+
+// contains is like image.Rectangle.In but includes the Max edges
+func (g *PowerGrid) contains(r image.Rectangle) bool {
+	b := g.Bounds
+	return b.Min.X <= r.Min.X && r.Min.X <= r.Max.X && r.Max.X <= b.Max.X &&
+		b.Min.Y <= r.Min.Y && r.Min.Y <= r.Max.Y && r.Max.Y <= b.Max.Y
+}
+
+// At returns the power level of a single cell
+func (g *PowerGrid) At(cell image.Point) int {
+	return g.Sum(image.Rectangle{cell, cell})
+}
@@ -0,0 +1,75 @@
+package day11
+
+import (
+	"image"
+	"testing"
+)
+
+func TestPowerGridSum(t *testing.T) {
+	bounds := []image.Rectangle{
+		image.Rect(1, 1, 12, 12),
+		image.Rect(5, 7, 20, 15),
+		image.Rect(-6, -3, 4, 2),
+		image.Rect(290, 1, 300, 9),
+		image.Rect(3, 3, 3, 3),
+	}
+	for _, b := range bounds {
+		grid := NewPowerGrid(18, b)
+		// every rectangle inside the bounds, including their Max edges
+		for y0 := b.Min.Y; y0 <= b.Max.Y; y0++ {
+			for x0 := b.Min.X; x0 <= b.Max.X; x0++ {
+				for y1 := y0; y1 <= b.Max.Y; y1++ {
+					for x1 := x0; x1 <= b.Max.X; x1++ {
+						var (
+							r    = image.Rect(x0, y0, x1, y1)
+							want int
+						)
+						ForEachCell(r, func(p image.Point) {
+							want += Power(p, 18)
+						})
+						if got := grid.Sum(r); got != want {
+							t.Fatalf("bounds %v: Sum(%v) = %d, want %d", b, r, got, want)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestPowerGridAt(t *testing.T) {
+	tests := []struct {
+		cell   image.Point
+		serial int
+		power  int
+	}{
+		{image.Pt(3, 5), 8, 4},
+		{image.Pt(122, 79), 57, -5},
+		{image.Pt(217, 196), 39, 0},
+		{image.Pt(101, 153), 71, 4},
+	}
+	for _, tt := range tests {
+		grid := NewPowerGrid(tt.serial, image.Rectangle{tt.cell.Sub(image.Pt(2, 2)), tt.cell.Add(image.Pt(1, 1))})
+		if got := grid.At(tt.cell); got != tt.power {
+			t.Errorf("serial %d: At(%v) = %d, want %d", tt.serial, tt.cell, got, tt.power)
+		}
+	}
+}
+
+func TestPowerGridOutside(t *testing.T) {
+	grid := NewPowerGrid(18, image.Rect(5, 5, 10, 10))
+	for _, r := range []image.Rectangle{
+		image.Rect(4, 5, 6, 6),
+		image.Rect(9, 9, 11, 10),
+		{Min: image.Pt(7, 7), Max: image.Pt(6, 8)},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Sum(%v) didn't panic", r)
+				}
+			}()
+			grid.Sum(r)
+		}()
+	}
+}
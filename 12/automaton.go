@@ -0,0 +1,242 @@
+package day12
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Boundary is how an automaton treats the ends of its tunnel
+type Boundary int
+
+const (
+	// Infinite tunnels are empty past their plants in both directions
+	Infinite Boundary = iota
+	// Wrap joins the ends of a tunnel with Size pots numbered from 0
+	Wrap
+)
+
+// Automaton is a one dimensional cellular automaton running on a Tunnel.
+// Unlike the puzzle's rules, its neighbourhoods can be any odd number of
+// pots wide.
+type Automaton struct {
+	// Width is the number of pots in a neighbourhood
+	Width int
+
+	// Table holds the next pot for every neighbourhood. It's indexed
+	// with the leftmost pot as the most significant bit, which is the
+	// same order as a Wolfram rule number's bits.
+	Table []Pot
+
+	Boundary Boundary
+	Size     int
+}
+
+// NewAutomaton creates an infinite automaton from rules in the puzzle's
+// format. Neighbourhoods without a rule produce an empty pot.
+func NewAutomaton(rules ...Rule) (*Automaton, error) {
+	if len(rules) == 0 {
+		return nil, fmt.Errorf("no rules")
+	}
+	width := len(rules[0].Pattern)
+	if width%2 == 0 {
+		return nil, fmt.Errorf("expected an odd neighbourhood width, got %d", width)
+	}
+	a := &Automaton{
+		Width: width,
+		Table: make([]Pot, 1<<uint(width)),
+	}
+	for _, r := range rules {
+		if len(r.Pattern) != width {
+			return nil, fmt.Errorf("expected rule pattern to be %d pots long, got %d", width, len(r.Pattern))
+		}
+		var index int
+		for _, p := range r.Pattern {
+			index <<= 1
+			if p {
+				index |= 1
+			}
+		}
+		a.Table[index] = r.To
+	}
+	return a, nil
+}
+
+// WolframRule creates an infinite automaton from a Wolfram rule number.
+// Bit i of the number is the next pot for neighbourhood i, so width 3
+// gives the elementary automata (rule 110, rule 30, ...). The number only
+// has room for widths up to 5.
+func WolframRule(number uint64, width int) (*Automaton, error) {
+	if width%2 == 0 || width < 1 || width > 5 {
+		return nil, fmt.Errorf("unsupported rule width: %d", width)
+	}
+	n := 1 << uint(width)
+	if n < 64 && number >= 1<<uint(n) {
+		return nil, fmt.Errorf("rule %d out of range for width %d", number, width)
+	}
+	a := &Automaton{
+		Width: width,
+		Table: make([]Pot, n),
+	}
+	for i := range a.Table {
+		a.Table[i] = number>>uint(i)&1 == 1
+	}
+	return a, nil
+}
+
+// TotalisticRule creates an infinite automaton where the next pot only
+// depends on how many plants are in the neighbourhood. Bit k of the code
+// is the next pot for a neighbourhood with k plants.
+func TotalisticRule(code uint64, width int) (*Automaton, error) {
+	if width%2 == 0 || width < 1 || width > 15 {
+		return nil, fmt.Errorf("unsupported rule width: %d", width)
+	}
+	if code >= 1<<uint(width+1) {
+		return nil, fmt.Errorf("totalistic code %d out of range for width %d", code, width)
+	}
+	a := &Automaton{
+		Width: width,
+		Table: make([]Pot, 1<<uint(width)),
+	}
+	for i := range a.Table {
+		var plants int
+		for j := i; j != 0; j >>= 1 {
+			plants += j & 1
+		}
+		a.Table[i] = code>>uint(plants)&1 == 1
+	}
+	return a, nil
+}
+
+// ParseAutomaton parses a rule description such as:
+//
+//	rule 110
+//	rule 110 wrap 80
+//	totalistic rule 20 width 5
+//
+// The width defaults to 3 and the tunnel is infinite unless it wraps.
+func ParseAutomaton(s string) (*Automaton, error) {
+	var (
+		fields     = strings.Fields(s)
+		totalistic bool
+		number     uint64
+		width      = 3
+		size       int
+		hasRule    bool
+		err        error
+	)
+	if len(fields) > 0 && fields[0] == "totalistic" {
+		totalistic = true
+		fields = fields[1:]
+	}
+	for len(fields) > 0 {
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("missing value for %q", fields[0])
+		}
+		key, value := fields[0], fields[1]
+		switch key {
+		case "rule":
+			number, err = strconv.ParseUint(value, 10, 64)
+			hasRule = true
+		case "width":
+			width, err = strconv.Atoi(value)
+		case "wrap":
+			size, err = strconv.Atoi(value)
+		default:
+			return nil, fmt.Errorf("unexpected %q", key)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %q", key, value)
+		}
+		fields = fields[2:]
+	}
+	if !hasRule {
+		return nil, fmt.Errorf("missing rule number")
+	}
+	var a *Automaton
+	if totalistic {
+		a, err = TotalisticRule(number, width)
+	} else {
+		a, err = WolframRule(number, width)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if size != 0 {
+		a.Boundary = Wrap
+		a.Size = size
+	}
+	return a, a.Validate()
+}
+
+// Radius returns the number of pots on either side of the center
+// which a neighbourhood covers.
+func (a *Automaton) Radius() int {
+	return a.Width / 2
+}
+
+// Validate checks that the automaton can be applied to a tunnel
+func (a *Automaton) Validate() error {
+	if a.Width%2 == 0 || len(a.Table) != 1<<uint(a.Width) {
+		return fmt.Errorf("invalid neighbourhood width: %d", a.Width)
+	}
+	switch a.Boundary {
+	case Infinite:
+		if a.Table[0] {
+			return fmt.Errorf("an empty neighbourhood can't produce a plant in an infinite tunnel")
+		}
+	case Wrap:
+		if a.Size < 1 {
+			return fmt.Errorf("invalid wrapped tunnel size: %d", a.Size)
+		}
+	default:
+		return fmt.Errorf("invalid boundary: %d", a.Boundary)
+	}
+	return nil
+}
+
+// at returns pot i with the boundary applied
+func (a *Automaton) at(t *Tunnel, i int) Pot {
+	if a.Boundary == Wrap {
+		i = (i%a.Size + a.Size) % a.Size
+	}
+	return t.At(i)
+}
+
+// Index returns the table index of the neighbourhood around center
+func (a *Automaton) Index(t *Tunnel, center int) int {
+	var index int
+	for i := center - a.Radius(); i <= center+a.Radius(); i++ {
+		index <<= 1
+		if a.at(t, i) {
+			index |= 1
+		}
+	}
+	return index
+}
+
+// Apply returns the next generation of t
+func (a *Automaton) Apply(t *Tunnel) *Tunnel {
+	next := NewTunnel()
+	if a.Boundary == Wrap {
+		for i := 0; i < a.Size; i++ {
+			next.SetAt(i, a.Table[a.Index(t, i)])
+		}
+		return next
+	}
+	radius := a.Radius()
+	t.Range(t.Min-radius, t.Max+radius, func(center int, _ Pot) {
+		if p := a.Table[a.Index(t, center)]; p {
+			next.SetAt(center, p)
+		}
+	})
+	return next
+}
+
+// Run returns the tunnel after applying the automaton for gen generations
+func (a *Automaton) Run(t *Tunnel, gen int) *Tunnel {
+	for i := 0; i < gen; i++ {
+		t = a.Apply(t)
+	}
+	return t
+}
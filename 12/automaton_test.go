@@ -0,0 +1,90 @@
+package day12
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAutomatonRule90(t *testing.T) {
+	sierpinski := []string{
+		"...............#...............",
+		"..............#.#..............",
+		".............#...#.............",
+		"............#.#.#.#............",
+		"...........#.......#...........",
+		"..........#.#.....#.#..........",
+		".........#...#...#...#.........",
+		"........#.#.#.#.#.#.#.#........",
+	}
+	for _, rule := range []string{"rule 90", "rule 90 wrap 31"} {
+		t.Run(rule, func(t *testing.T) {
+			a, err := ParseAutomaton(rule)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tunnel := NewTunnel()
+			tunnel.SetAt(15, true)
+			for gen, want := range sierpinski {
+				if got := tunnel.RangeString(0, 30); got != want {
+					t.Fatalf("generation %d: got %s, want %s", gen, got, want)
+				}
+				tunnel = a.Apply(tunnel)
+			}
+		})
+	}
+}
+
+func TestAutomatonRule110Wrap(t *testing.T) {
+	const size = 8
+	a, err := ParseAutomaton("rule 110 wrap 8")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pots, err := ParsePots("#.##...#")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tunnel := NewTunnel()
+	tunnel.Init(pots)
+	for gen := 1; gen <= 20; gen++ {
+		// the first and last pots are neighbours
+		next := make([]Pot, size)
+		for i := range next {
+			var index uint
+			for _, p := range []Pot{pots[(i+size-1)%size], pots[i], pots[(i+1)%size]} {
+				index <<= 1
+				if p {
+					index |= 1
+				}
+			}
+			next[i] = 110>>index&1 == 1
+		}
+		pots = next
+		tunnel = a.Apply(tunnel)
+		var want strings.Builder
+		for _, p := range pots {
+			want.WriteString(p.String())
+		}
+		if got := tunnel.RangeString(0, size-1); got != want.String() {
+			t.Fatalf("generation %d: got %s, want %s", gen, got, want.String())
+		}
+	}
+}
+
+func TestAutomatonEmptyNeighbourhood(t *testing.T) {
+	for _, rule := range []string{"rule 1", "rule 255", "totalistic rule 1 width 5"} {
+		if _, err := ParseAutomaton(rule); err == nil {
+			t.Errorf("%s: expected an error for an infinite tunnel", rule)
+		}
+		if _, err := ParseAutomaton(rule + " wrap 8"); err != nil {
+			t.Errorf("%s: unexpected error in a wrapped tunnel: %v", rule, err)
+		}
+	}
+	a, err := NewAutomaton(Rule{Pattern: make([]Pot, 5), To: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Validate(); err == nil {
+		t.Fatal("expected an error for a rule producing a plant from an empty neighbourhood")
+	}
+}
@@ -0,0 +1,154 @@
+package day12
+
+import (
+	"fmt"
+	"math/bits"
+	"strings"
+)
+
+// RuleTable is a set of rules compiled into a lookup table. It's indexed
+// by a 5 pot neighbourhood with the leftmost pot as the most significant bit.
+type RuleTable [32]Pot
+
+// CompileRules builds a lookup table from rules. Neighbourhoods without a
+// rule produce an empty pot and later rules win, just like Tunnel.Apply.
+func CompileRules(rules ...Rule) (*RuleTable, error) {
+	var table RuleTable
+	for _, r := range rules {
+		if len(r.Pattern) != 5 {
+			return nil, fmt.Errorf("expected rule pattern to be 5 pots long, got %d", len(r.Pattern))
+		}
+		var index int
+		for _, p := range r.Pattern {
+			index <<= 1
+			if p {
+				index |= 1
+			}
+		}
+		table[index] = r.To
+	}
+	if table[0] {
+		return nil, fmt.Errorf("rule %s would fill the infinite tunnel with plants", Rule{Pattern: make([]Pot, 5), To: true})
+	}
+	return &table, nil
+}
+
+// BitTunnel is a Tunnel which packs its pots into a bitset.
+// Bit i is the pot numbered Offset+i.
+type BitTunnel struct {
+	Offset int
+	Bits   []uint64
+}
+
+// NewBitTunnel creates a tunnel with the initial pots numbered from 0
+func NewBitTunnel(pots []Pot) *BitTunnel {
+	b := &BitTunnel{
+		Bits: make([]uint64, (len(pots)+63)/64),
+	}
+	for i, p := range pots {
+		if p {
+			b.Bits[i/64] |= 1 << uint(i%64)
+		}
+	}
+	return b.trim()
+}
+
+// size returns the number of pots covered by the bitset
+func (b *BitTunnel) size() int {
+	return len(b.Bits) * 64
+}
+
+// bit returns bit i, which is 0 outside of the bitset
+func (b *BitTunnel) bit(i int) int {
+	if i < 0 || i >= b.size() {
+		return 0
+	}
+	return int(b.Bits[i/64]>>uint(i%64)) & 1
+}
+
+func (b *BitTunnel) At(i int) Pot {
+	return b.bit(i-b.Offset) == 1
+}
+
+// Apply returns the next generation. Each pot is found by sliding a
+// 5 bit window along the tunnel and looking it up in the table.
+func (b *BitTunnel) Apply(table *RuleTable) *BitTunnel {
+	// the plants can spread at most 2 pots past either end
+	var (
+		size = b.size() + 4
+		next = &BitTunnel{
+			Offset: b.Offset - 2,
+			Bits:   make([]uint64, (size+63)/64),
+		}
+		window int
+	)
+	for i := 0; i < size; i++ {
+		// pot i of the next generation is centered on pot i-2 of this one
+		window = (window<<1 | b.bit(i)) & 31
+		if table[window] {
+			next.Bits[i/64] |= 1 << uint(i%64)
+		}
+	}
+	return next.trim()
+}
+
+// trim removes the empty words from either end of the bitset
+func (b *BitTunnel) trim() *BitTunnel {
+	for len(b.Bits) > 0 && b.Bits[len(b.Bits)-1] == 0 {
+		b.Bits = b.Bits[:len(b.Bits)-1]
+	}
+	for len(b.Bits) > 0 && b.Bits[0] == 0 {
+		b.Bits = b.Bits[1:]
+		b.Offset += 64
+	}
+	return b
+}
+
+// Count returns the number of pots with plants
+func (b *BitTunnel) Count() int {
+	var n int
+	for _, w := range b.Bits {
+		n += bits.OnesCount64(w)
+	}
+	return n
+}
+
+func (b *BitTunnel) PlantNumSum() int {
+	var sum int
+	for i, w := range b.Bits {
+		for w != 0 {
+			j := bits.TrailingZeros64(w)
+			sum += b.Offset + i*64 + j
+			w &= w - 1
+		}
+	}
+	return sum
+}
+
+// Extents returns the numbers of the first and last pots with plants
+func (b *BitTunnel) Extents() (min, max int) {
+	if len(b.Bits) == 0 {
+		return 0, 0
+	}
+	first, last := b.Bits[0], b.Bits[len(b.Bits)-1]
+	min = b.Offset + bits.TrailingZeros64(first)
+	max = b.Offset + b.size() - 1 - bits.LeadingZeros64(last)
+	return min, max
+}
+
+// Shape returns the pots between the first and last plants
+func (b *BitTunnel) Shape() string {
+	if len(b.Bits) == 0 {
+		return ""
+	}
+	var s strings.Builder
+	min, max := b.Extents()
+	for i := min; i <= max; i++ {
+		s.WriteString(b.At(i).String())
+	}
+	return s.String()
+}
+
+func (b *BitTunnel) String() string {
+	return b.Shape()
+}
@@ -0,0 +1,105 @@
+package day12
+
+import (
+	"os"
+	"testing"
+)
+
+func readInput(t *testing.T, name string) *Input {
+	t.Helper()
+	f, err := os.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	input, err := ParseInput(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return input
+}
+
+func TestBitTunnel(t *testing.T) {
+	for _, name := range []string{"example.txt", "input.txt"} {
+		t.Run(name, func(t *testing.T) {
+			input := readInput(t, name)
+			table, err := CompileRules(input.Rules...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var (
+				tunnel = NewTunnel()
+				bits   = NewBitTunnel(input.State)
+			)
+			tunnel.Init(input.State)
+			for gen := 0; gen <= 200; gen++ {
+				if got, want := bits.Shape(), tunnel.Shape(); got != want {
+					t.Fatalf("generation %d: shape is %q, want %q", gen, got, want)
+				}
+				min, max := tunnel.Extents()
+				if bmin, bmax := bits.Extents(); bmin != min || bmax != max {
+					t.Fatalf("generation %d: extents are %d-%d, want %d-%d", gen, bmin, bmax, min, max)
+				}
+				if got, want := bits.Count(), tunnel.Count(); got != want {
+					t.Fatalf("generation %d: count is %d, want %d", gen, got, want)
+				}
+				if got, want := bits.PlantNumSum(), tunnel.PlantNumSum(); got != want {
+					t.Fatalf("generation %d: plant number sum is %d, want %d", gen, got, want)
+				}
+				tunnel = tunnel.Apply(input.Rules...)
+				bits = bits.Apply(table)
+			}
+		})
+	}
+}
+
+func TestPlantNumSumAt(t *testing.T) {
+	for _, name := range []string{"example.txt", "input.txt"} {
+		t.Run(name, func(t *testing.T) {
+			input := readInput(t, name)
+			table, err := CompileRules(input.Rules...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var (
+				bits = NewBitTunnel(input.State)
+				gen  int
+			)
+			for _, at := range []int{0, 1, 20, 99, 100, 101, 257, 1000, 5000} {
+				for ; gen < at; gen++ {
+					bits = bits.Apply(table)
+				}
+				tunnel := NewTunnel()
+				tunnel.Init(input.State)
+				got, err := tunnel.PlantNumSumAt(at, input.Rules...)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if want := bits.PlantNumSum(); got != want {
+					t.Fatalf("generation %d: plant number sum is %d, want %d", at, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestPlantNumSumAtNoRepeat(t *testing.T) {
+	// rule 90 grows a Sierpinski triangle which never repeats its shape
+	var rules []Rule
+	for i := 0; i < 32; i++ {
+		pattern := make([]Pot, 5)
+		for j := range pattern {
+			pattern[j] = i>>uint(4-j)&1 == 1
+		}
+		rules = append(rules, Rule{Pattern: pattern, To: pattern[1] != pattern[3]})
+	}
+	tunnel := NewTunnel()
+	tunnel.Init([]Pot{true})
+	if _, err := tunnel.PlantNumSumAt(50000000000, rules...); err == nil {
+		t.Fatal("expected an error for plants which never repeat")
+	}
+	// there's no need to find a repeat when every generation is simulated
+	if _, err := tunnel.PlantNumSumAt(100, rules...); err != nil {
+		t.Fatal(err)
+	}
+}
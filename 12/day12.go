@@ -1,21 +1,35 @@
-package main
+package day12
 
 import (
 	"bufio"
 	"fmt"
-	"log"
-	"os"
+	"io"
+	"strconv"
 	"strings"
+
+	"github.com/icholy/advent"
 )
 
-func main() {
-	input, err := ReadInput("input.txt")
+type Solver struct{}
+
+func (Solver) Parse(r io.Reader) (advent.Input, error) {
+	return ParseInput(r)
+}
+
+func (Solver) PartOne(in advent.Input) (string, error) {
+	return strconv.Itoa(PartOne(in.(*Input))), nil
+}
+
+func (Solver) PartTwo(in advent.Input) (string, error) {
+	sum, err := PartTwo(in.(*Input))
 	if err != nil {
-		log.Fatal(err)
+		return "", err
 	}
+	return strconv.Itoa(sum), nil
+}
 
-	fmt.Printf("Answer (Part 1): %d\n", PartOne(input))
-	fmt.Printf("Answer (Part 2): %d\n", PartTwo())
+func init() {
+	advent.Register(12, Solver{})
 }
 
 func PartOne(input *Input) int {
@@ -27,20 +41,10 @@ func PartOne(input *Input) int {
 	return tunnel.PlantNumSum()
 }
 
-// after 100 iterations this shape keeps repeating. We can use this to compute the n-th generation.
-const RepeatingShape = "#..##..#..#..##..#..#..##..#..#..##..#..#..##..#..#..##..#..#..##..#..#..##..#..#..##..#..#..##..#..#..##..##....#..##..##..##..#..#..##....#..##"
-
-func PartTwo() int {
-	gen := 50000000000
-	pots, err := ParsePots(RepeatingShape)
-	if err != nil {
-		log.Fatal(err)
-	}
+func PartTwo(input *Input) (int, error) {
 	tunnel := NewTunnel()
-	for i, p := range pots {
-		tunnel.SetAt(gen+i-45, p)
-	}
-	return tunnel.PlantNumSum()
+	tunnel.Init(input.State)
+	return tunnel.PlantNumSumAt(50000000000, input.Rules...)
 }
 
 type Pot bool
@@ -67,6 +71,51 @@ func (t *Tunnel) PlantNumSum() int {
 	return sum
 }
 
+// Count returns the number of pots with plants
+func (t *Tunnel) Count() int {
+	return len(t.Pots)
+}
+
+// RepeatLimit is the number of generations PlantNumSumAt searches
+// for a repeating shape before giving up
+const RepeatLimit = 1000
+
+// PlantNumSumAt returns the plant number sum after applying the rules
+// for gen generations. The tunnel itself isn't modified.
+//
+// Once the shape of the plants repeats, every period after that moves
+// the same shape the same distance along the tunnel. So only the first
+// repeat needs to be found, and the remaining periods are extrapolated.
+// Shapes which don't repeat within RepeatLimit generations are an error
+// unless gen is small enough to simulate them all.
+func (t *Tunnel) PlantNumSumAt(gen int, rules ...Rule) (int, error) {
+	type seen struct {
+		gen, min int
+	}
+	shapes := map[string]seen{}
+	for i := 0; i < gen; i++ {
+		if i == RepeatLimit {
+			return 0, fmt.Errorf("the plants don't repeat within %d generations", RepeatLimit)
+		}
+		shape := t.Shape()
+		min, _ := t.Extents()
+		if prev, ok := shapes[shape]; ok {
+			var (
+				period    = i - prev.gen
+				drift     = min - prev.min
+				remaining = gen - i
+			)
+			for j := 0; j < remaining%period; j++ {
+				t = t.Apply(rules...)
+			}
+			return t.PlantNumSum() + remaining/period*drift*t.Count(), nil
+		}
+		shapes[shape] = seen{i, min}
+		t = t.Apply(rules...)
+	}
+	return t.PlantNumSum(), nil
+}
+
 func (t *Tunnel) At(i int) Pot {
 	return t.Pots[i]
 }
@@ -137,8 +186,14 @@ func (t *Tunnel) Init(pots []Pot) {
 }
 
 func (t *Tunnel) Apply(rules ...Rule) *Tunnel {
+	var radius int
+	for _, r := range rules {
+		if r.Radius() > radius {
+			radius = r.Radius()
+		}
+	}
 	next := NewTunnel()
-	t.Range(t.Min-2, t.Max+2, func(center int, p Pot) {
+	t.Range(t.Min-radius, t.Max+radius, func(center int, p Pot) {
 		for _, r := range rules {
 			if r.Matches(t, center) {
 				next.SetAt(center, r.To)
@@ -174,12 +229,20 @@ func (r Rule) String() string {
 	return fmt.Sprintf("%s => %s", pattern.String(), r.To)
 }
 
+// Radius returns the number of pots on either side of the center
+// which the rule's pattern covers.
+func (r Rule) Radius() int {
+	return len(r.Pattern) / 2
+}
+
 func (r Rule) Matches(t *Tunnel, center int) bool {
-	return t.At(center-2) == r.Pattern[0] &&
-		t.At(center-1) == r.Pattern[1] &&
-		t.At(center) == r.Pattern[2] &&
-		t.At(center+1) == r.Pattern[3] &&
-		t.At(center+2) == r.Pattern[4]
+	start := center - r.Radius()
+	for i, p := range r.Pattern {
+		if t.At(start+i) != p {
+			return false
+		}
+	}
+	return true
 }
 
 type Input struct {
@@ -198,13 +261,8 @@ func ParsePots(s string) ([]Pot, error) {
 	return pots, nil
 }
 
-func ReadInput(file string) (*Input, error) {
-	f, err := os.Open(file)
-	if err != nil {
-		return nil, err
-	}
-	defer f.Close()
-	sc := bufio.NewScanner(f)
+func ParseInput(r io.Reader) (*Input, error) {
+	sc := bufio.NewScanner(r)
 
 	// read the initial state
 	if !sc.Scan() {
@@ -236,8 +294,11 @@ func ReadInput(file string) (*Input, error) {
 		if err != nil {
 			return nil, fmt.Errorf("failed to parse rule pattern: %v", err)
 		}
-		if len(rule.Pattern) != 5 {
-			return nil, fmt.Errorf("expected rule pattern to be 5 pots long, got %d", len(rule.Pattern))
+		if len(rule.Pattern)%2 == 0 {
+			return nil, fmt.Errorf("expected rule pattern to be an odd number of pots long, got %d", len(rule.Pattern))
+		}
+		if len(rules) > 0 && len(rule.Pattern) != len(rules[0].Pattern) {
+			return nil, fmt.Errorf("expected rule pattern to be %d pots long, got %d", len(rules[0].Pattern), len(rule.Pattern))
 		}
 		rule.To = to == "#"
 		rules = append(rules, rule)
@@ -0,0 +1,153 @@
+package day13
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"io"
+	"time"
+
+	"github.com/icholy/advent"
+	"github.com/icholy/draw"
+)
+
+// Keys understood by a Replay
+const (
+	KeyPause = ' '
+	KeyStep  = 'n'
+	KeyQuit  = 'q'
+)
+
+// terminal escape sequences used to draw the frames
+const (
+	clearScreen = "\x1b[H\x1b[2J"
+	newCrash    = "\x1b[1;37;41m"
+	oldCrash    = "\x1b[31m"
+	resetColor  = "\x1b[0m"
+)
+
+// Replay animates a simulation in a terminal. Crashes are highlighted
+// on the tick they happen and stay marked in red afterwards.
+type Replay struct {
+	Sim    *Simulation
+	Width  int
+	Height int
+	Paused bool
+
+	// seen is the number of collisions drawn by the previous frame
+	seen int
+}
+
+// NewReplay creates a replay of sim drawn on a w by h canvas
+func NewReplay(sim *Simulation, w, h int) *Replay {
+	return &Replay{Sim: sim, Width: w, Height: h}
+}
+
+// Done reports whether there are no more collisions to watch
+func (r *Replay) Done() bool {
+	return r.Sim.NumCarts() < 2
+}
+
+// Step advances the simulation by a single tick
+func (r *Replay) Step() {
+	r.seen = len(r.Sim.Collisions)
+	r.Sim.Tick()
+}
+
+// Frame writes the current state of the simulation followed by a status line
+func (r *Replay) Frame(w io.Writer) error {
+	var (
+		cv      = draw.NewCanvas(r.Width, r.Height)
+		crashes = map[image.Point]string{}
+		bw      = bufio.NewWriter(w)
+	)
+	cv.Draw(r.Sim, 0)
+	for i, p := range r.Sim.Collisions {
+		if i < r.seen {
+			crashes[p] = oldCrash
+		} else {
+			crashes[p] = newCrash
+		}
+	}
+	bw.WriteString(clearScreen)
+	for y := 0; y < cv.Height(); y++ {
+		for x := 0; x < cv.Width(); x++ {
+			b := cv.At(x, y)
+			// a cart can drive over an old crash site, so only the
+			// crashes which are still showing are coloured
+			if color, ok := crashes[image.Pt(x, y)]; ok && b == 'X' {
+				fmt.Fprintf(bw, "%s%c%s", color, b, resetColor)
+			} else {
+				bw.WriteByte(b)
+			}
+		}
+		bw.WriteString("\n")
+	}
+	fmt.Fprintf(bw, "tick %d, %d carts, %d crashes", r.Sim.Ticks, r.Sim.NumCarts(), len(r.Sim.Collisions))
+	switch {
+	case r.Done():
+		bw.WriteString(" (done)")
+	case r.Paused:
+		bw.WriteString(" (paused: space resumes, n steps)")
+	}
+	bw.WriteString("\n")
+	return bw.Flush()
+}
+
+// Play draws a frame for every tick until the simulation is done or
+// KeyQuit is received from keys. KeyPause toggles between playing and
+// pausing, and KeyStep moves a paused simulation forward by one tick.
+func (r *Replay) Play(w io.Writer, keys <-chan byte, fps int) error {
+	if fps < 1 || fps > advent.MaxFPS {
+		return fmt.Errorf("invalid frame rate: %d", fps)
+	}
+	ticker := time.NewTicker(time.Second / time.Duration(fps))
+	defer ticker.Stop()
+	dirty := true
+	for {
+		if dirty {
+			if err := r.Frame(w); err != nil {
+				return err
+			}
+			dirty = false
+		}
+		if r.Done() {
+			return nil
+		}
+		select {
+		case k, ok := <-keys:
+			if !ok {
+				// nothing left to read, keep playing without controls
+				keys = nil
+				r.Paused = false
+				continue
+			}
+			switch k {
+			case KeyPause:
+				r.Paused = !r.Paused
+				dirty = true
+			case KeyStep:
+				if r.Paused {
+					r.Step()
+					dirty = true
+				}
+			case KeyQuit:
+				return nil
+			}
+		case <-ticker.C:
+			if !r.Paused {
+				r.Step()
+				dirty = true
+			}
+		}
+	}
+}
+
+func (Solver) Animate(in advent.Input, w io.Writer, keys <-chan byte, fps int) error {
+	input := in.(Input)
+	sim, err := input.Simulation()
+	if err != nil {
+		return err
+	}
+	return NewReplay(sim, input.Canvas.Width(), input.Canvas.Height()).Play(w, keys, fps)
+}
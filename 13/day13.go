@@ -0,0 +1,539 @@
+package day13
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/icholy/advent"
+	"github.com/icholy/draw"
+)
+
+// Load parses the tracks on cv into a validated simulation
+func Load(cv draw.Canvas) (*Simulation, error) {
+	g, carts, err := ParseGraph(cv)
+	if err != nil {
+		return nil, err
+	}
+	sim := NewSimulation(g, carts)
+	if err := sim.Validate(); err != nil {
+		return nil, err
+	}
+	return sim, nil
+}
+
+// PartOne returns the location of the first crash. It's predicted from
+// the carts' trajectories unless their policy can't be predicted, in
+// which case the simulation is ticked until a crash happens.
+func PartOne(sim *Simulation) (image.Point, error) {
+	if sim.NumCarts() < 2 {
+		return image.ZP, fmt.Errorf("no collision possible with %d carts", sim.NumCarts())
+	}
+	if !Predictable(sim) {
+		return simulateFirstCrash(sim)
+	}
+	pred, err := Predict(sim)
+	if err != nil {
+		return image.ZP, err
+	}
+	if len(pred.Crashes) == 0 {
+		return image.ZP, fmt.Errorf("none of the %d carts ever crash", sim.NumCarts())
+	}
+	return pred.Crashes[0].Position, nil
+}
+
+// PartTwo returns the location of the last cart left after all others
+// crashed. Like PartOne, it only ticks when the policy can't be predicted.
+func PartTwo(sim *Simulation) (image.Point, error) {
+	if !Predictable(sim) {
+		return simulateLastCart(sim)
+	}
+	pred, err := Predict(sim)
+	if err != nil {
+		return image.ZP, err
+	}
+	c, ok := pred.LastCart()
+	if !ok {
+		if len(pred.Survivors) == 0 {
+			return image.ZP, fmt.Errorf("no carts left")
+		}
+		return image.ZP, fmt.Errorf("%d carts never crash", len(pred.Survivors))
+	}
+	return c.Position, nil
+}
+
+// MaxTicks is the most ticks the parts simulate
+// when the carts' policy can't be predicted
+const MaxTicks = 1000000
+
+// tracks counts the carts which haven't crashed on each separate
+// piece of track. Carts on different pieces can never meet.
+func tracks(sim *Simulation) map[int]int {
+	var (
+		components = sim.Graph.Components()
+		counts     = map[int]int{}
+	)
+	for _, c := range sim.Carts {
+		if !c.Crashed {
+			counts[components[c.Node]]++
+		}
+	}
+	return counts
+}
+
+// simulateFirstCrash ticks sim until the first crash
+func simulateFirstCrash(sim *Simulation) (image.Point, error) {
+	var shared bool
+	for _, n := range tracks(sim) {
+		shared = shared || n > 1
+	}
+	if !shared {
+		return image.ZP, fmt.Errorf("none of the %d carts share a track", sim.NumCarts())
+	}
+	for len(sim.Collisions) == 0 {
+		if sim.NumCarts() < 2 {
+			return image.ZP, fmt.Errorf("no collision possible with %d carts", sim.NumCarts())
+		}
+		if sim.Ticks >= MaxTicks {
+			return image.ZP, fmt.Errorf("no crash within %d ticks", MaxTicks)
+		}
+		sim.Tick()
+	}
+	return sim.Collisions[0], nil
+}
+
+// simulateLastCart ticks sim until there's at most one cart left
+func simulateLastCart(sim *Simulation) (image.Point, error) {
+	// carts crash in pairs, so a track with an odd number
+	// of carts always has one left on it
+	var odd int
+	for _, n := range tracks(sim) {
+		if n%2 == 1 {
+			odd++
+		}
+	}
+	if odd > 1 {
+		return image.ZP, fmt.Errorf("carts will be left on %d separate tracks", odd)
+	}
+	for sim.NumCarts() > 1 {
+		if sim.Ticks >= MaxTicks {
+			return image.ZP, fmt.Errorf("%d carts left after %d ticks", sim.NumCarts(), MaxTicks)
+		}
+		sim.Tick()
+	}
+	for _, c := range sim.Carts {
+		if !c.Crashed {
+			return c.Position, nil
+		}
+	}
+	return image.ZP, fmt.Errorf("no carts left")
+}
+
+// Input is the map of the tracks and the policy the carts turn with.
+// It's kept as a canvas rather than a simulation because each part
+// needs to start from a fresh one.
+type Input struct {
+	Canvas draw.Canvas
+	Policy string
+}
+
+// Simulation loads a fresh simulation of the input
+func (in Input) Simulation() (*Simulation, error) {
+	policy, err := ParsePolicy(in.Policy)
+	if err != nil {
+		return nil, err
+	}
+	sim, err := Load(in.Canvas)
+	if err != nil {
+		return nil, err
+	}
+	sim.Policy = policy
+	return sim, nil
+}
+
+type Solver struct{}
+
+func (Solver) Parse(r io.Reader) (advent.Input, error) {
+	cv, err := ParseCanvas(r)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := Load(cv); err != nil {
+		return nil, err
+	}
+	return Input{Canvas: cv, Policy: "cycle"}, nil
+}
+
+// Configure sets the "policy" option to a description understood by
+// ParsePolicy, which lets the outcomes of other policies be compared
+func (Solver) Configure(in advent.Input, name, value string) (advent.Input, error) {
+	input := in.(Input)
+	switch name {
+	case "policy":
+		if _, err := ParsePolicy(value); err != nil {
+			return nil, err
+		}
+		input.Policy = value
+		return input, nil
+	default:
+		return nil, fmt.Errorf("unknown option: %q", name)
+	}
+}
+
+func (Solver) PartOne(in advent.Input) (string, error) {
+	sim, err := in.(Input).Simulation()
+	if err != nil {
+		return "", err
+	}
+	p, err := PartOne(sim)
+	if err != nil {
+		return "", err
+	}
+	return p.String(), nil
+}
+
+func (Solver) PartTwo(in advent.Input) (string, error) {
+	sim, err := in.(Input).Simulation()
+	if err != nil {
+		return "", err
+	}
+	p, err := PartTwo(sim)
+	if err != nil {
+		return "", err
+	}
+	return p.String(), nil
+}
+
+func init() {
+	advent.Register(13, Solver{})
+}
+
+type Direction byte
+
+func (d Direction) Valid() bool {
+	switch d {
+	case North, South, East, West:
+		return true
+	default:
+		return false
+	}
+}
+
+func (d Direction) Turn(t Turn) Direction {
+	if t != Left && t != Right {
+		return d
+	}
+	switch d {
+	case North:
+		if t == Left {
+			return West
+		} else {
+			return East
+		}
+	case South:
+		if t == Left {
+			return East
+		} else {
+			return West
+		}
+	case East:
+		if t == Left {
+			return North
+		} else {
+			return South
+		}
+	case West:
+		if t == Left {
+			return South
+		} else {
+			return North
+		}
+	}
+	return d
+}
+
+// Opposite returns the direction facing the other way
+func (d Direction) Opposite() Direction {
+	switch d {
+	case North:
+		return South
+	case South:
+		return North
+	case East:
+		return West
+	case West:
+		return East
+	}
+	return d
+}
+
+// Delta returns the offset of one step in direction d
+func (d Direction) Delta() image.Point {
+	switch d {
+	case North:
+		return image.Pt(0, -1)
+	case South:
+		return image.Pt(0, 1)
+	case East:
+		return image.Pt(1, 0)
+	case West:
+		return image.Pt(-1, 0)
+	}
+	return image.ZP
+}
+
+func (d Direction) String() string {
+	switch d {
+	case North:
+		return "north"
+	case South:
+		return "south"
+	case East:
+		return "east"
+	case West:
+		return "west"
+	}
+	return fmt.Sprintf("Direction(%q)", byte(d))
+}
+
+const (
+	North = Direction('^')
+	South = Direction('v')
+	East  = Direction('>')
+	West  = Direction('<')
+)
+
+type Turn int
+
+const (
+	Left = Turn(iota)
+	Strait
+	Right
+)
+
+func (t Turn) String() string {
+	switch t {
+	case Left:
+		return "left"
+	case Strait:
+		return "straight"
+	case Right:
+		return "right"
+	default:
+		return fmt.Sprintf("Turn(%d)", int(t))
+	}
+}
+
+func (t Turn) Next() Turn {
+	switch t {
+	case Left:
+		return Strait
+	case Strait:
+		return Right
+	case Right:
+		return Left
+	default:
+		panic("invalid turn")
+	}
+}
+
+type Cart struct {
+	// ID is the cart's index in reading order of the starting positions
+	ID        int
+	Direction Direction
+	Position  image.Point
+	Node      *Node
+	Crashed   bool
+
+	// Intersections is the number of intersections the cart has passed
+	Intersections int
+}
+
+// LessThan orders carts by row and then by column
+func (c *Cart) LessThan(other *Cart) bool {
+	if c.Position.Y != other.Position.Y {
+		return c.Position.Y < other.Position.Y
+	}
+	return c.Position.X < other.Position.X
+}
+
+// Intersect turns the cart at an intersection the way the policy
+// decides and returns the turn it took
+func (c *Cart) Intersect(p TurnPolicy) Turn {
+	t := p.Turn(c)
+	c.Direction = c.Direction.Turn(t)
+	c.Intersections++
+	return t
+}
+
+// Step moves the cart to the next piece of track and points it in the
+// direction it will leave that piece in. When the piece is an intersection
+// it returns the turn taken and true.
+func (c *Cart) Step(p TurnPolicy) (Turn, bool) {
+	next, ok := c.Node.Links[c.Direction]
+	if !ok {
+		panic("cart ran off the track")
+	}
+	c.Node = next
+	c.Position = next.Position
+	if next.Kind == Intersection {
+		return c.Intersect(p), true
+	}
+	c.Direction = next.Exit(c.Direction)
+	return Strait, false
+}
+
+func (c Cart) Draw(cv draw.Canvas, _ byte) {
+	p := draw.FromImagePoint(c.Position)
+	cv.Draw(p, byte(c.Direction))
+}
+
+type Simulation struct {
+	Graph      *Graph
+	Carts      []*Cart
+	Collisions []image.Point
+	Occupied   map[image.Point]*Cart
+	Ticks      int
+
+	// Policy decides how carts turn at intersections, it defaults to CyclePolicy
+	Policy TurnPolicy
+
+	// OnEvent is called with everything that happens during a tick
+	OnEvent func(Event)
+}
+
+func NewSimulation(g *Graph, carts []*Cart) *Simulation {
+	sim := &Simulation{
+		Graph:    g,
+		Carts:    carts,
+		Occupied: make(map[image.Point]*Cart),
+	}
+	for _, c := range sim.Carts {
+		sim.Occupied[c.Position] = c
+	}
+	return sim
+}
+
+func (sim *Simulation) NumCarts() int {
+	return len(sim.Occupied)
+}
+
+// emit sends an event for the current tick to OnEvent
+func (sim *Simulation) emit(e Event) {
+	if sim.OnEvent != nil {
+		e.Tick = sim.Ticks
+		sim.OnEvent(e)
+	}
+}
+
+func (sim *Simulation) Tick() {
+	sim.Ticks++
+	policy := sim.Policy
+	if policy == nil {
+		policy = CyclePolicy{}
+	}
+	sort.Slice(sim.Carts, func(i, j int) bool {
+		return sim.Carts[i].LessThan(sim.Carts[j])
+	})
+
+	for _, c := range sim.Carts {
+
+		if c.Crashed {
+			continue
+		}
+
+		delete(sim.Occupied, c.Position)
+		turn, turned := c.Step(policy)
+		sim.emit(Event{Kind: MoveEvent, Cart: c.ID, Position: c.Position, Direction: c.Direction})
+		if turned {
+			sim.emit(Event{Kind: TurnEvent, Cart: c.ID, Position: c.Position, Direction: c.Direction, Turn: turn})
+		}
+
+		if other, ok := sim.Occupied[c.Position]; ok {
+			c.Crashed = true
+			other.Crashed = true
+			sim.Collisions = append(sim.Collisions, c.Position)
+			delete(sim.Occupied, c.Position)
+			sim.emit(Event{Kind: CrashEvent, Cart: c.ID, Other: other.ID, Position: c.Position})
+			sim.emit(Event{Kind: RemoveEvent, Cart: c.ID, Position: c.Position})
+			sim.emit(Event{Kind: RemoveEvent, Cart: other.ID, Position: c.Position})
+		} else {
+			sim.Occupied[c.Position] = c
+		}
+	}
+}
+
+func (sim *Simulation) TickN(n int) {
+	for i := 0; i < n; i++ {
+		sim.Tick()
+	}
+}
+
+// Validate checks that every cart is on the track and heading along it
+func (sim *Simulation) Validate() error {
+	for _, c := range sim.Carts {
+		if c.Node == nil {
+			return fmt.Errorf("cart at %v isn't on the track", c.Position)
+		}
+		if _, ok := c.Node.Links[c.Direction]; !ok {
+			return fmt.Errorf("cart at %v is heading %v off the track", c.Position, c.Direction)
+		}
+	}
+	return nil
+}
+
+func (sim *Simulation) Draw(cv draw.Canvas, _ byte) {
+	cv.Draw(sim.Graph, 0)
+	for _, p := range sim.Collisions {
+		cv.Draw(draw.FromImagePoint(p), 'X')
+	}
+	for _, c := range sim.Carts {
+		if !c.Crashed {
+			cv.Draw(c, 0)
+		}
+	}
+}
+
+// MaxLineLength is the longest line ParseCanvas will read
+const MaxLineLength = 1 << 20
+
+// ParseCanvas reads the map onto a canvas sized to fit it. Every line
+// must be the same length and only contain track, carts or spaces.
+func ParseCanvas(r io.Reader) (draw.Canvas, error) {
+	var (
+		sc    = bufio.NewScanner(r)
+		lines []string
+		width int
+	)
+	sc.Buffer(nil, MaxLineLength)
+	for sc.Scan() {
+		line := strings.TrimSuffix(sc.Text(), "\r")
+		if len(lines) == 0 {
+			width = len(line)
+		} else if len(line) != width {
+			return nil, fmt.Errorf("line %d is %d characters long, expected %d", len(lines)+1, len(line), width)
+		}
+		if i := strings.IndexFunc(line, func(r rune) bool {
+			return !strings.ContainsRune(" -|/\\+^v<>", r)
+		}); i != -1 {
+			return nil, fmt.Errorf("line %d column %d: unexpected %q", len(lines)+1, i+1, line[i])
+		}
+		lines = append(lines, line)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if width == 0 {
+		return nil, fmt.Errorf("empty map")
+	}
+	cv := draw.NewCanvas(width, len(lines))
+	for y, line := range lines {
+		for x := 0; x < len(line); x++ {
+			cv.Draw(draw.FromImagePoint(image.Pt(x, y)), line[x])
+		}
+	}
+	return cv, nil
+}
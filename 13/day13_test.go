@@ -0,0 +1,57 @@
+package day13
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+)
+
+func TestParseCanvas(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		width, height int
+	}{
+		{"rectangle", "/-\\\n\\-/\n", 3, 2},
+		{"crlf", "/-\\\r\n\\-/\r\n", 3, 2},
+		{"no trailing newline", "/-\\\n\\-/", 3, 2},
+		{"wide", strings.Repeat("-", 100000), 100000, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cv, err := ParseCanvas(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cv.Width() != tt.width || cv.Height() != tt.height {
+				t.Fatalf("canvas is %dx%d, want %dx%d", cv.Width(), cv.Height(), tt.width, tt.height)
+			}
+		})
+	}
+}
+
+func TestParseCanvasErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		err   string
+	}{
+		{"ragged", "/--\\\n\\-/\n", "line 2 is 3 characters long, expected 4"},
+		{"ragged blank line", "/-\\\n\n\\-/\n", "line 2 is 0 characters long, expected 3"},
+		{"bad character", "/-\\\n\\?/\n", "line 2 column 2: unexpected '?'"},
+		{"empty", "", "empty map"},
+		{"blank", "\n\n", "empty map"},
+		{"too wide", strings.Repeat("-", MaxLineLength+1), bufio.ErrTooLong.Error()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseCanvas(strings.NewReader(tt.input))
+			if err == nil {
+				t.Fatalf("expected error %q", tt.err)
+			}
+			if err.Error() != tt.err {
+				t.Fatalf("got error %q, want %q", err, tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package day13
+
+import (
+	"fmt"
+	"image"
+)
+
+// EventKind is what happened to a cart
+type EventKind int
+
+const (
+	// MoveEvent is sent after a cart moves onto its next piece of track
+	MoveEvent EventKind = iota
+	// TurnEvent is sent after a cart takes its turn at an intersection
+	TurnEvent
+	// CrashEvent is sent when a cart moves into Other
+	CrashEvent
+	// RemoveEvent is sent for both carts after a crash
+	RemoveEvent
+)
+
+func (k EventKind) String() string {
+	switch k {
+	case MoveEvent:
+		return "move"
+	case TurnEvent:
+		return "turn"
+	case CrashEvent:
+		return "crash"
+	case RemoveEvent:
+		return "remove"
+	default:
+		return fmt.Sprintf("EventKind(%d)", int(k))
+	}
+}
+
+// Event is something that happened to a cart during a tick. The
+// direction is the one the cart is heading in afterwards.
+type Event struct {
+	Tick      int
+	Kind      EventKind
+	Cart      int
+	Other     int
+	Position  image.Point
+	Direction Direction
+	Turn      Turn
+}
+
+func (e Event) String() string {
+	switch e.Kind {
+	case MoveEvent:
+		return fmt.Sprintf("tick %d: cart %d moved to %v heading %v", e.Tick, e.Cart, e.Position, e.Direction)
+	case TurnEvent:
+		return fmt.Sprintf("tick %d: cart %d turned %v at %v", e.Tick, e.Cart, e.Turn, e.Position)
+	case CrashEvent:
+		return fmt.Sprintf("tick %d: cart %d crashed into cart %d at %v", e.Tick, e.Cart, e.Other, e.Position)
+	case RemoveEvent:
+		return fmt.Sprintf("tick %d: cart %d removed at %v", e.Tick, e.Cart, e.Position)
+	default:
+		return fmt.Sprintf("tick %d: %v cart %d", e.Tick, e.Kind, e.Cart)
+	}
+}
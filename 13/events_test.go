@@ -0,0 +1,95 @@
+package day13
+
+import (
+	"image"
+	"os"
+	"testing"
+
+	"github.com/icholy/draw"
+)
+
+func readCanvas(t *testing.T, name string) draw.Canvas {
+	t.Helper()
+	f, err := os.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	cv, err := ParseCanvas(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cv
+}
+
+func TestEvents(t *testing.T) {
+	sim, err := Load(readCanvas(t, "example2.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	sim.OnEvent = func(e Event) {
+		got = append(got, e.String())
+	}
+	for sim.NumCarts() > 1 {
+		sim.Tick()
+	}
+	want := []string{
+		"tick 1: cart 0 moved to (2,0) heading east",
+		"tick 1: cart 1 moved to (2,0) heading west",
+		"tick 1: cart 1 crashed into cart 0 at (2,0)",
+		"tick 1: cart 1 removed at (2,0)",
+		"tick 1: cart 0 removed at (2,0)",
+		"tick 1: cart 2 moved to (2,2) heading south",
+		"tick 1: cart 3 moved to (6,4) heading south",
+		"tick 1: cart 4 moved to (2,4) heading north",
+		"tick 1: cart 4 turned left at (2,4)",
+		"tick 1: cart 5 moved to (2,4) heading south",
+		"tick 1: cart 5 turned left at (2,4)",
+		"tick 1: cart 5 crashed into cart 4 at (2,4)",
+		"tick 1: cart 5 removed at (2,4)",
+		"tick 1: cart 4 removed at (2,4)",
+		"tick 1: cart 6 moved to (6,4) heading north",
+		"tick 1: cart 6 crashed into cart 3 at (6,4)",
+		"tick 1: cart 6 removed at (6,4)",
+		"tick 1: cart 3 removed at (6,4)",
+		"tick 1: cart 7 moved to (2,6) heading north",
+		"tick 1: cart 8 moved to (6,6) heading north",
+		"tick 2: cart 2 moved to (2,3) heading south",
+		"tick 2: cart 7 moved to (2,5) heading north",
+		"tick 2: cart 8 moved to (6,5) heading north",
+		"tick 3: cart 2 moved to (2,4) heading east",
+		"tick 3: cart 2 turned left at (2,4)",
+		"tick 3: cart 7 moved to (2,4) heading west",
+		"tick 3: cart 7 turned left at (2,4)",
+		"tick 3: cart 7 crashed into cart 2 at (2,4)",
+		"tick 3: cart 7 removed at (2,4)",
+		"tick 3: cart 2 removed at (2,4)",
+		"tick 3: cart 8 moved to (6,4) heading north",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d events, want %d:\n%v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("event %d is %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestLessThan(t *testing.T) {
+	var carts []*Cart
+	for y := 0; y < 3; y++ {
+		for x := 0; x < 3; x++ {
+			carts = append(carts, &Cart{Position: image.Pt(x, y)})
+		}
+	}
+	// carts is in reading order, so only earlier carts are less
+	for i, a := range carts {
+		for j, b := range carts {
+			if got, want := a.LessThan(b), i < j; got != want {
+				t.Errorf("%v.LessThan(%v) = %v, want %v", a.Position, b.Position, got, want)
+			}
+		}
+	}
+}
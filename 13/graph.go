@@ -0,0 +1,241 @@
+package day13
+
+import (
+	"fmt"
+	"image"
+	"sort"
+
+	"github.com/icholy/draw"
+)
+
+// Kinds of track pieces
+const (
+	Horizontal   = '-'
+	Vertical     = '|'
+	Slash        = '/'
+	Backslash    = '\\'
+	Intersection = '+'
+)
+
+// Directions lists every direction in a fixed order
+var Directions = []Direction{North, East, South, West}
+
+// Node is a single piece of track. Links holds the neighbouring
+// pieces it connects to, keyed by the direction they're in.
+type Node struct {
+	Position image.Point
+	Kind     byte
+	Links    map[Direction]*Node
+}
+
+// IsCurve reports whether the node is a corner piece
+func (n *Node) IsCurve() bool {
+	return n.Kind == Slash || n.Kind == Backslash
+}
+
+// Exit returns the direction a cart leaves the node in after arriving
+// while heading in direction d. Intersections are left the way the
+// cart was heading, turning is up to the cart.
+func (n *Node) Exit(d Direction) Direction {
+	if !n.IsCurve() {
+		return d
+	}
+	for dir := range n.Links {
+		if dir != d.Opposite() {
+			return dir
+		}
+	}
+	return d
+}
+
+func (n *Node) Draw(cv draw.Canvas, _ byte) {
+	cv.Draw(draw.FromImagePoint(n.Position), n.Kind)
+}
+
+// Graph is the track network with a node for every piece of track
+type Graph struct {
+	Nodes map[image.Point]*Node
+}
+
+// Positions returns the position of every node in reading order
+func (g *Graph) Positions() []image.Point {
+	var pp []image.Point
+	for p := range g.Nodes {
+		pp = append(pp, p)
+	}
+	sort.Slice(pp, func(i, j int) bool {
+		if pp[i].Y != pp[j].Y {
+			return pp[i].Y < pp[j].Y
+		}
+		return pp[i].X < pp[j].X
+	})
+	return pp
+}
+
+// Components numbers the separate pieces of track. Nodes which are
+// linked to each other, directly or not, get the same number.
+func (g *Graph) Components() map[*Node]int {
+	var (
+		components = map[*Node]int{}
+		id         int
+	)
+	for _, p := range g.Positions() {
+		if _, ok := components[g.Nodes[p]]; ok {
+			continue
+		}
+		id++
+		stack := []*Node{g.Nodes[p]}
+		for len(stack) > 0 {
+			n := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if _, ok := components[n]; ok {
+				continue
+			}
+			components[n] = id
+			for _, next := range n.Links {
+				stack = append(stack, next)
+			}
+		}
+	}
+	return components
+}
+
+func (g *Graph) Draw(cv draw.Canvas, _ byte) {
+	for _, n := range g.Nodes {
+		cv.Draw(n, 0)
+	}
+}
+
+// ParseGraph builds the track graph from the canvas and returns it along
+// with the carts found on it. Carts are assumed to start on straight
+// track. Every piece of track must connect to its neighbours in both
+// directions, so loose ends and stray characters are reported as errors.
+func ParseGraph(cv draw.Canvas) (*Graph, []*Cart, error) {
+	var (
+		g     = &Graph{Nodes: map[image.Point]*Node{}}
+		carts []*Cart
+	)
+	for y := 0; y < cv.Height(); y++ {
+		for x := 0; x < cv.Width(); x++ {
+			var (
+				b = cv.At(x, y)
+				p = image.Pt(x, y)
+			)
+			switch b {
+			case 0, ' ':
+				continue
+			case Horizontal, Vertical, Slash, Backslash, Intersection:
+			case byte(North), byte(South):
+				carts = append(carts, &Cart{ID: len(carts), Direction: Direction(b), Position: p})
+				b = Vertical
+			case byte(East), byte(West):
+				carts = append(carts, &Cart{ID: len(carts), Direction: Direction(b), Position: p})
+				b = Horizontal
+			default:
+				return nil, nil, fmt.Errorf("unexpected %q at %v", b, p)
+			}
+			g.Nodes[p] = &Node{Position: p, Kind: b, Links: map[Direction]*Node{}}
+		}
+	}
+	// work out which way every piece of track leads before linking them,
+	// because the curves depend on their neighbours.
+	var (
+		positions = g.Positions()
+		exits     = map[*Node][]Direction{}
+	)
+	for _, p := range positions {
+		n := g.Nodes[p]
+		dd, err := g.exits(n)
+		if err != nil {
+			return nil, nil, err
+		}
+		exits[n] = dd
+	}
+	for _, p := range positions {
+		n := g.Nodes[p]
+		for _, d := range exits[n] {
+			next, ok := g.Nodes[n.Position.Add(d.Delta())]
+			if !ok || !hasDirection(exits[next], d.Opposite()) {
+				return nil, nil, fmt.Errorf("track at %v leads %v to %v, which doesn't lead back", n.Position, d, n.Position.Add(d.Delta()))
+			}
+			n.Links[d] = next
+		}
+	}
+	for _, c := range carts {
+		c.Node = g.Nodes[c.Position]
+	}
+	return g, carts, nil
+}
+
+// curves lists the two ways each corner piece can be joined up
+var curves = map[byte][2][2]Direction{
+	Slash:     {{East, South}, {West, North}},
+	Backslash: {{West, South}, {North, East}},
+}
+
+// exits returns the directions that n leads in. A curve takes the pair
+// of directions whose neighbours can lead back into it, preferring
+// neighbours which are straight track or intersections.
+func (g *Graph) exits(n *Node) ([]Direction, error) {
+	switch n.Kind {
+	case Horizontal:
+		return []Direction{East, West}, nil
+	case Vertical:
+		return []Direction{North, South}, nil
+	case Intersection:
+		return Directions, nil
+	}
+	var (
+		best  []Direction
+		score = -1
+		tie   bool
+	)
+	for _, pair := range curves[n.Kind] {
+		s, ok := g.joins(n, pair)
+		if !ok {
+			continue
+		}
+		switch {
+		case s > score:
+			best, score, tie = []Direction{pair[0], pair[1]}, s, false
+		case s == score:
+			tie = true
+		}
+	}
+	switch {
+	case best == nil:
+		return nil, fmt.Errorf("curve at %v doesn't join two tracks", n.Position)
+	case tie:
+		return nil, fmt.Errorf("curve at %v is ambiguous", n.Position)
+	}
+	return best, nil
+}
+
+// joins reports whether the neighbours in both directions of pair could
+// lead back into n. The score is the number of them which definitely do.
+func (g *Graph) joins(n *Node, pair [2]Direction) (score int, ok bool) {
+	for _, d := range pair {
+		next, ok := g.Nodes[n.Position.Add(d.Delta())]
+		if !ok {
+			return 0, false
+		}
+		if next.IsCurve() {
+			continue
+		}
+		dd, _ := g.exits(next)
+		if !hasDirection(dd, d.Opposite()) {
+			return 0, false
+		}
+		score++
+	}
+	return score, true
+}
+
+func hasDirection(dd []Direction, d Direction) bool {
+	for _, dir := range dd {
+		if dir == d {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,171 @@
+package day13
+
+import (
+	"image"
+	"strings"
+	"testing"
+
+	"github.com/icholy/draw"
+)
+
+// canvas pads the lines to the same length and parses them
+func canvas(t *testing.T, lines ...string) draw.Canvas {
+	t.Helper()
+	var width int
+	for _, l := range lines {
+		if len(l) > width {
+			width = len(l)
+		}
+	}
+	for i, l := range lines {
+		lines[i] = l + strings.Repeat(" ", width-len(l))
+	}
+	cv, err := ParseCanvas(strings.NewReader(strings.Join(lines, "\n")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cv
+}
+
+// component returns the number of nodes linked to the one at p
+func component(g *Graph, p image.Point) int {
+	var (
+		seen  = map[*Node]bool{}
+		stack = []*Node{g.Nodes[p]}
+	)
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if seen[n] {
+			continue
+		}
+		seen[n] = true
+		for _, next := range n.Links {
+			stack = append(stack, next)
+		}
+	}
+	return len(seen)
+}
+
+func TestParseGraph(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+		// components are the sizes of the loops starting at each point
+		components map[image.Point]int
+	}{
+		{
+			name: "non-rectangular loop",
+			lines: []string{
+				`/--\`,
+				`|  |`,
+				`|  \-\`,
+				`|    |`,
+				`\----/`,
+			},
+			components: map[image.Point]int{{0, 0}: 18},
+		},
+		{
+			name: "adjacent parallel tracks",
+			lines: []string{
+				`/----\`,
+				`|/--\|`,
+				`||  ||`,
+				`|\--/|`,
+				`\----/`,
+			},
+			components: map[image.Point]int{{0, 0}: 18, {1, 1}: 10},
+		},
+		{
+			name: "figure eight",
+			lines: []string{
+				`/-\`,
+				`| |`,
+				`\-+-\`,
+				`  | |`,
+				`  \-/`,
+			},
+			components: map[image.Point]int{{0, 0}: 15},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, _, err := ParseGraph(canvas(t, tt.lines...))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, n := range g.Nodes {
+				want := 2
+				if n.Kind == Intersection {
+					want = 4
+				}
+				if len(n.Links) != want {
+					t.Fatalf("%q at %v has %d links, want %d", n.Kind, n.Position, len(n.Links), want)
+				}
+				for d, next := range n.Links {
+					if next.Links[d.Opposite()] != n {
+						t.Fatalf("%v leads %v to %v, which doesn't lead back", n.Position, d, next.Position)
+					}
+				}
+			}
+			for p, want := range tt.components {
+				if got := component(g, p); got != want {
+					t.Fatalf("the track at %v has %d pieces, want %d", p, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseGraphErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+		err   string
+	}{
+		{
+			name:  "dangling arrow",
+			lines: []string{`-->`},
+			err:   "track at (0,0) leads west to (-1,0), which doesn't lead back",
+		},
+		{
+			name: "dangling arrow off a loop",
+			lines: []string{
+				`/-\`,
+				`| |-->`,
+				`\-/`,
+			},
+			err: "track at (3,1) leads west to (2,1), which doesn't lead back",
+		},
+		{
+			name:  "lone intersection",
+			lines: []string{`+`},
+			err:   "track at (0,0) leads north to (0,-1), which doesn't lead back",
+		},
+		{
+			name:  "lone curve",
+			lines: []string{`/`},
+			err:   "curve at (0,0) doesn't join two tracks",
+		},
+		{
+			name: "ambiguous corner",
+			lines: []string{
+				` | `,
+				`-/-`,
+				` | `,
+			},
+			err: "curve at (1,1) is ambiguous",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := ParseGraph(canvas(t, tt.lines...))
+			if err == nil {
+				t.Fatalf("expected an error containing %q", tt.err)
+			}
+			if !strings.Contains(err.Error(), tt.err) {
+				t.Fatalf("got error %q, want it to contain %q", err, tt.err)
+			}
+		})
+	}
+}
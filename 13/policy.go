@@ -0,0 +1,123 @@
+package day13
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+// TurnPolicy decides which way a cart turns at an intersection
+type TurnPolicy interface {
+	Turn(c *Cart) Turn
+}
+
+// CyclePolicy is the puzzle's policy. Every cart turns left, goes
+// straight, then turns right and repeats.
+type CyclePolicy struct{}
+
+func (CyclePolicy) Turn(c *Cart) Turn {
+	t := Left
+	for i := 0; i < c.Intersections%3; i++ {
+		t = t.Next()
+	}
+	return t
+}
+
+// StraightPolicy never turns
+type StraightPolicy struct{}
+
+func (StraightPolicy) Turn(*Cart) Turn { return Strait }
+
+// RandomPolicy picks each turn at random. The same seed and track
+// always produce the same turns.
+type RandomPolicy struct {
+	rand *rand.Rand
+}
+
+func NewRandomPolicy(seed int64) *RandomPolicy {
+	return &RandomPolicy{rand.New(rand.NewSource(seed))}
+}
+
+func (p *RandomPolicy) Turn(*Cart) Turn {
+	return Turn(p.rand.Intn(3))
+}
+
+// ScriptPolicy gives carts their own sequence of turns which repeats
+// once it runs out. Carts without a script use the Fallback policy,
+// or CyclePolicy when it's nil.
+type ScriptPolicy struct {
+	Scripts  map[int][]Turn
+	Fallback TurnPolicy
+}
+
+func (p ScriptPolicy) Turn(c *Cart) Turn {
+	if script := p.Scripts[c.ID]; len(script) > 0 {
+		return script[c.Intersections%len(script)]
+	}
+	if p.Fallback != nil {
+		return p.Fallback.Turn(c)
+	}
+	return CyclePolicy{}.Turn(c)
+}
+
+// ParsePolicy parses a policy description such as:
+//
+//	cycle
+//	straight
+//	random 42
+//	script 0:LSR 3:SS
+//
+// The scripts are keyed by cart ID and use L, S and R for the turns.
+func ParsePolicy(s string) (TurnPolicy, error) {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("missing policy")
+	}
+	name, args := fields[0], fields[1:]
+	switch name {
+	case "cycle", "straight":
+		if len(args) != 0 {
+			return nil, fmt.Errorf("unexpected %q", args[0])
+		}
+		if name == "cycle" {
+			return CyclePolicy{}, nil
+		}
+		return StraightPolicy{}, nil
+	case "random":
+		if len(args) != 1 {
+			return nil, fmt.Errorf("expected a seed for the random policy")
+		}
+		seed, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid seed: %q", args[0])
+		}
+		return NewRandomPolicy(seed), nil
+	case "script":
+		p := ScriptPolicy{Scripts: map[int][]Turn{}}
+		for _, arg := range args {
+			var (
+				id    int
+				turns string
+			)
+			if _, err := fmt.Sscanf(arg, "%d:%s", &id, &turns); err != nil {
+				return nil, fmt.Errorf("invalid script: %q", arg)
+			}
+			for _, r := range turns {
+				switch r {
+				case 'L':
+					p.Scripts[id] = append(p.Scripts[id], Left)
+				case 'S':
+					p.Scripts[id] = append(p.Scripts[id], Strait)
+				case 'R':
+					p.Scripts[id] = append(p.Scripts[id], Right)
+				default:
+					return nil, fmt.Errorf("invalid turn %q in script: %q", r, arg)
+				}
+			}
+		}
+		return p, nil
+	default:
+		return nil, fmt.Errorf("unknown policy: %q", name)
+	}
+}
@@ -0,0 +1,61 @@
+package day13
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePolicy(t *testing.T) {
+	tests := []struct {
+		s    string
+		want TurnPolicy
+	}{
+		{"cycle", CyclePolicy{}},
+		{"straight", StraightPolicy{}},
+		{"random 42", NewRandomPolicy(42)},
+		{
+			"script 0:LSR 3:SS",
+			ScriptPolicy{Scripts: map[int][]Turn{
+				0: {Left, Strait, Right},
+				3: {Strait, Strait},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		got, err := ParsePolicy(tt.s)
+		if err != nil {
+			t.Errorf("%q: %v", tt.s, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q: got %#v, want %#v", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestParsePolicyErrors(t *testing.T) {
+	tests := []struct {
+		s   string
+		err string
+	}{
+		{"", "missing policy"},
+		{"cycle 1", `unexpected "1"`},
+		{"straight ahead", `unexpected "ahead"`},
+		{"random", "expected a seed for the random policy"},
+		{"random 1 2", "expected a seed for the random policy"},
+		{"random abc", `invalid seed: "abc"`},
+		{"script LSR", `invalid script: "LSR"`},
+		{"script 0:LXR", `invalid turn 'X' in script: "0:LXR"`},
+		{"zigzag", `unknown policy: "zigzag"`},
+	}
+	for _, tt := range tests {
+		_, err := ParsePolicy(tt.s)
+		if err == nil {
+			t.Errorf("%q: expected error %q", tt.s, tt.err)
+			continue
+		}
+		if err.Error() != tt.err {
+			t.Errorf("%q: got error %q, want %q", tt.s, err, tt.err)
+		}
+	}
+}
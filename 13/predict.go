@@ -0,0 +1,300 @@
+package day13
+
+import (
+	"fmt"
+	"image"
+	"sort"
+)
+
+// Crash is a collision between two carts. Carts[0] is the cart which
+// moved into Carts[1].
+type Crash struct {
+	Tick     int
+	Position image.Point
+	Carts    [2]int
+}
+
+// Prediction is the outcome of a simulation once no more carts can crash
+type Prediction struct {
+	Crashes []Crash
+
+	// Survivors are the carts which never crash. Their positions are
+	// the ones they have at the end of the last crash's tick.
+	Survivors []Cart
+}
+
+// LastCart returns the only cart left after all the others crash
+func (p *Prediction) LastCart() (Cart, bool) {
+	if len(p.Survivors) != 1 {
+		return Cart{}, false
+	}
+	return p.Survivors[0], true
+}
+
+// Trajectory is the repeating path of a single cart. Positions[i] is
+// where the cart is i ticks after Start, modulo the period.
+type Trajectory struct {
+	Cart      int
+	Start     int
+	Positions []image.Point
+}
+
+// Period returns the number of ticks before the cart repeats its path
+func (t *Trajectory) Period() int {
+	return len(t.Positions)
+}
+
+// At returns the position of the cart i ticks after Start
+func (t *Trajectory) At(i int) image.Point {
+	return t.Positions[mod(i, len(t.Positions))]
+}
+
+// phases returns how many intersections it takes for the policy's
+// choices to repeat for c. It's false for policies which don't repeat.
+func phases(p TurnPolicy, c *Cart) (int, bool) {
+	switch p := p.(type) {
+	case nil, CyclePolicy:
+		return 3, true
+	case StraightPolicy:
+		return 1, true
+	case ScriptPolicy:
+		if script := p.Scripts[c.ID]; len(script) > 0 {
+			return len(script), true
+		}
+		return phases(p.Fallback, c)
+	default:
+		return 0, false
+	}
+}
+
+// Predictable reports whether the turns of every cart in sim repeat,
+// which is what Predict needs to work out their trajectories
+func Predictable(sim *Simulation) bool {
+	for _, c := range sim.Carts {
+		if _, ok := phases(sim.Policy, c); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Trace follows a copy of c until it gets back to the same piece of
+// track, heading the same way, at the same point in its turn policy.
+// Carts can't merge into the same path, so every path is a loop.
+func Trace(c *Cart, p TurnPolicy, start int) (*Trajectory, error) {
+	n, ok := phases(p, c)
+	if !ok {
+		return nil, fmt.Errorf("can't predict the turns of %T", p)
+	}
+	if p == nil {
+		p = CyclePolicy{}
+	}
+	type state struct {
+		node  *Node
+		dir   Direction
+		phase int
+	}
+	var (
+		cc    = *c
+		first = state{cc.Node, cc.Direction, cc.Intersections % n}
+		t     = &Trajectory{Cart: c.ID, Start: start}
+	)
+	for {
+		t.Positions = append(t.Positions, cc.Position)
+		cc.Step(p)
+		if (state{cc.Node, cc.Direction, cc.Intersections % n}) == first {
+			return t, nil
+		}
+	}
+}
+
+// Predict works out every crash that will happen in sim from the carts'
+// trajectories. It takes time proportional to the length of the loops
+// the carts travel rather than the number of ticks before they crash.
+func Predict(sim *Simulation) (*Prediction, error) {
+	var (
+		carts  = map[int]*Cart{}
+		paths  []*Trajectory
+		visits = map[image.Point][]visit{}
+	)
+	for _, c := range sim.Carts {
+		if c.Crashed {
+			continue
+		}
+		t, err := Trace(c, sim.Policy, sim.Ticks)
+		if err != nil {
+			return nil, err
+		}
+		carts[c.ID] = c
+		paths = append(paths, t)
+		for i, p := range t.Positions {
+			visits[p] = append(visits[p], visit{t, i})
+		}
+	}
+	// only the first crash between each pair of carts can happen
+	first := map[[2]int]crashEvent{}
+	for _, vv := range visits {
+		for i, a := range vv {
+			for _, b := range vv[i+1:] {
+				if a.path == b.path {
+					continue
+				}
+				for _, e := range crashes(a, b) {
+					key := [2]int{a.path.Cart, b.path.Cart}
+					if f, ok := first[key]; !ok || e.before(f) {
+						first[key] = e
+					}
+				}
+			}
+		}
+	}
+	var events []crashEvent
+	for _, e := range first {
+		events = append(events, e)
+	}
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].before(events[j])
+	})
+	var (
+		pred    = &Prediction{}
+		crashed = map[int]bool{}
+	)
+	for _, e := range events {
+		if crashed[e.Crash.Carts[0]] || crashed[e.Crash.Carts[1]] {
+			continue
+		}
+		crashed[e.Crash.Carts[0]] = true
+		crashed[e.Crash.Carts[1]] = true
+		pred.Crashes = append(pred.Crashes, e.Crash)
+	}
+	last := sim.Ticks
+	if len(pred.Crashes) > 0 {
+		last = pred.Crashes[len(pred.Crashes)-1].Tick
+	}
+	for _, t := range paths {
+		if crashed[t.Cart] {
+			continue
+		}
+		c := *carts[t.Cart]
+		c.Position = t.At(last - t.Start)
+		c.Node = sim.Graph.Nodes[c.Position]
+		pred.Survivors = append(pred.Survivors, c)
+	}
+	sort.Slice(pred.Survivors, func(i, j int) bool {
+		return pred.Survivors[i].ID < pred.Survivors[j].ID
+	})
+	return pred, nil
+}
+
+// visit is a cart being on a piece of track Offset ticks into its trajectory
+type visit struct {
+	path   *Trajectory
+	offset int
+}
+
+// crashEvent is a crash along with the position the moving cart
+// started the tick at, which decides the order of crashes in a tick.
+type crashEvent struct {
+	Crash
+	from image.Point
+}
+
+func (e crashEvent) before(other crashEvent) bool {
+	if e.Tick != other.Tick {
+		return e.Tick < other.Tick
+	}
+	if e.from.Y != other.from.Y {
+		return e.from.Y < other.from.Y
+	}
+	return e.from.X < other.from.X
+}
+
+// crashes returns the first tick at which a and b could crash at the
+// piece of track they both visit. Carts move one at a time in reading
+// order, so a cart crashes if it moves onto a cart which has already
+// moved there, or onto a cart which hasn't moved yet this tick.
+func crashes(a, b visit) []crashEvent {
+	var ee []crashEvent
+	// both end the tick here, the one which moves second crashes
+	if tick, ok := meet(a.path, a.offset, b.path, b.offset); ok {
+		var (
+			fromA = a.path.At(tick - a.path.Start - 1)
+			fromB = b.path.At(tick - b.path.Start - 1)
+		)
+		if readingOrder(fromA, fromB) {
+			ee = append(ee, event(tick, b, a, fromB))
+		} else {
+			ee = append(ee, event(tick, a, b, fromA))
+		}
+	}
+	// one moves onto the other before it moves away
+	for _, v := range [][2]visit{{a, b}, {b, a}} {
+		mover, other := v[0], v[1]
+		if tick, ok := meet(mover.path, mover.offset, other.path, other.offset+1); ok {
+			from := mover.path.At(tick - mover.path.Start - 1)
+			if readingOrder(from, other.path.At(tick-other.path.Start-1)) {
+				ee = append(ee, event(tick, mover, other, from))
+			}
+		}
+	}
+	return ee
+}
+
+func event(tick int, mover, other visit, from image.Point) crashEvent {
+	return crashEvent{
+		Crash: Crash{
+			Tick:     tick,
+			Position: mover.path.At(tick - mover.path.Start),
+			Carts:    [2]int{mover.path.Cart, other.path.Cart},
+		},
+		from: from,
+	}
+}
+
+// meet returns the first tick after both trajectories start where a is
+// offset i into its period and b is offset j into its period.
+func meet(a *Trajectory, i int, b *Trajectory, j int) (int, bool) {
+	// the trajectories start on the same tick, so this is a pair of
+	// congruences on the number of ticks since then
+	x, m, ok := crt(i, a.Period(), j, b.Period())
+	if !ok {
+		return 0, false
+	}
+	if x == 0 {
+		x = m
+	}
+	return a.Start + x, true
+}
+
+// crt solves x ≡ a (mod n) and x ≡ b (mod m). It returns the smallest
+// non-negative solution and the modulus that all solutions share.
+func crt(a, n, b, m int) (int, int, bool) {
+	g, p, _ := egcd(n, m)
+	if mod(b-a, g) != 0 {
+		return 0, 0, false
+	}
+	l := n / g * m
+	// n*p ≡ g (mod m), so stepping by n*p*(b-a)/g moves a onto b
+	k := mod((b-a)/g*p, m/g)
+	return mod(a+n*k, l), l, true
+}
+
+// egcd returns g = gcd(a, b) along with x and y where a*x + b*y = g
+func egcd(a, b int) (g, x, y int) {
+	if b == 0 {
+		return a, 1, 0
+	}
+	g, x, y = egcd(b, a%b)
+	return g, y, x - a/b*y
+}
+
+func mod(a, n int) int {
+	return (a%n + n) % n
+}
+
+func readingOrder(a, b image.Point) bool {
+	if a.Y != b.Y {
+		return a.Y < b.Y
+	}
+	return a.X < b.X
+}
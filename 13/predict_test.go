@@ -0,0 +1,119 @@
+package day13
+
+import (
+	"image"
+	"reflect"
+	"testing"
+)
+
+// loadPolicy loads a simulation of the named map using the policy
+func loadPolicy(t *testing.T, name, policy string) *Simulation {
+	t.Helper()
+	sim, err := Input{Canvas: readCanvas(t, name), Policy: policy}.Simulation()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sim
+}
+
+func TestPredict(t *testing.T) {
+	policies := []string{"cycle", "straight", "script 0:LSR 1:RRS 2:S"}
+	for _, name := range []string{"example.txt", "example2.txt", "input.txt"} {
+		for _, policy := range policies {
+			t.Run(name+"/"+policy, func(t *testing.T) {
+				pred, err := Predict(loadPolicy(t, name, policy))
+				if err != nil {
+					t.Fatal(err)
+				}
+				var (
+					sim     = loadPolicy(t, name, policy)
+					crashes []Crash
+					last    int
+				)
+				sim.OnEvent = func(e Event) {
+					if e.Kind == CrashEvent {
+						crashes = append(crashes, Crash{e.Tick, e.Position, [2]int{e.Cart, e.Other}})
+					}
+				}
+				if n := len(pred.Crashes); n > 0 {
+					last = pred.Crashes[n-1].Tick
+				}
+				sim.TickN(last)
+				if !reflect.DeepEqual(crashes, pred.Crashes) {
+					t.Fatalf("ticking crashed %v, predicted %v", crashes, pred.Crashes)
+				}
+				if sim.NumCarts() != len(pred.Survivors) {
+					t.Fatalf("%d carts are left, predicted %d", sim.NumCarts(), len(pred.Survivors))
+				}
+				for _, s := range pred.Survivors {
+					for _, c := range sim.Carts {
+						if c.ID == s.ID && (c.Crashed || c.Position != s.Position) {
+							t.Fatalf("cart %d is at %v, predicted %v", c.ID, c.Position, s.Position)
+						}
+					}
+				}
+				sim.TickN(1000)
+				if len(crashes) != len(pred.Crashes) {
+					t.Fatalf("crashes after the last predicted one: %v", crashes[len(pred.Crashes):])
+				}
+			})
+		}
+	}
+}
+
+func TestPredictNoCrash(t *testing.T) {
+	// two loops which never meet
+	in := Input{
+		Canvas: canvas(t,
+			`/>\ /<\`,
+			`\-/ \-/`,
+		),
+		Policy: "cycle",
+	}
+	for n, part := range []func(*Simulation) (image.Point, error){PartOne, PartTwo} {
+		sim, err := in.Simulation()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p, err := part(sim); err == nil {
+			t.Errorf("part %d: expected an error, got %v", n+1, p)
+		}
+	}
+}
+
+func TestSimulateNeverCrash(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+	}{
+		{
+			name: "separate tracks",
+			lines: []string{
+				`/>\ /<\`,
+				`\-/ \-/`,
+			},
+		},
+		{
+			name: "chasing",
+			lines: []string{
+				`/>-\`,
+				`|  |`,
+				`\-</`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := Input{Canvas: canvas(t, tt.lines...), Policy: "random 1"}
+			for n, part := range []func(*Simulation) (image.Point, error){PartOne, PartTwo} {
+				sim, err := in.Simulation()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if p, err := part(sim); err == nil {
+					t.Errorf("part %d: expected an error, got %v", n+1, p)
+				}
+			}
+		})
+	}
+}
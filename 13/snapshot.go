@@ -0,0 +1,175 @@
+package day13
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"sort"
+)
+
+// Snapshot is the state of a simulation at the end of a tick. It doesn't
+// include the track, so it can only be restored onto a simulation loaded
+// from the same map. The state of a RandomPolicy isn't saved either.
+type Snapshot struct {
+	Ticks      int           `json:"ticks"`
+	Carts      []CartState   `json:"carts"`
+	Collisions []image.Point `json:"collisions"`
+}
+
+// CartState is the saved state of a single cart
+type CartState struct {
+	ID            int         `json:"id"`
+	Position      image.Point `json:"position"`
+	Direction     Direction   `json:"direction"`
+	Intersections int         `json:"intersections"`
+	Crashed       bool        `json:"crashed,omitempty"`
+}
+
+func (d Direction) MarshalText() ([]byte, error) {
+	if !d.Valid() {
+		return nil, fmt.Errorf("invalid direction: %q", byte(d))
+	}
+	return []byte(d.String()), nil
+}
+
+func (d *Direction) UnmarshalText(text []byte) error {
+	for _, dir := range Directions {
+		if dir.String() == string(text) {
+			*d = dir
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid direction: %q", text)
+}
+
+// Snapshot returns a copy of the simulation's current state
+func (sim *Simulation) Snapshot() *Snapshot {
+	s := &Snapshot{
+		Ticks:      sim.Ticks,
+		Collisions: append([]image.Point{}, sim.Collisions...),
+	}
+	for _, c := range sim.Carts {
+		s.Carts = append(s.Carts, CartState{
+			ID:            c.ID,
+			Position:      c.Position,
+			Direction:     c.Direction,
+			Intersections: c.Intersections,
+			Crashed:       c.Crashed,
+		})
+	}
+	return s
+}
+
+// Restore replaces the simulation's state with the snapshot. It checks
+// that the carts are on the simulation's track before changing anything,
+// so the simulation must already have one.
+func (sim *Simulation) Restore(s *Snapshot) error {
+	if sim.Graph == nil {
+		return fmt.Errorf("can't restore a snapshot without a track")
+	}
+	var (
+		carts    []*Cart
+		occupied = map[image.Point]*Cart{}
+	)
+	for _, cs := range s.Carts {
+		c := &Cart{
+			ID:            cs.ID,
+			Direction:     cs.Direction,
+			Position:      cs.Position,
+			Node:          sim.Graph.Nodes[cs.Position],
+			Crashed:       cs.Crashed,
+			Intersections: cs.Intersections,
+		}
+		if c.Node == nil {
+			return fmt.Errorf("cart %d at %v isn't on the track", c.ID, c.Position)
+		}
+		if !c.Crashed {
+			if _, ok := c.Node.Links[c.Direction]; !ok {
+				return fmt.Errorf("cart %d at %v is heading %v off the track", c.ID, c.Position, c.Direction)
+			}
+			if other, ok := occupied[c.Position]; ok {
+				return fmt.Errorf("carts %d and %d are both at %v", other.ID, c.ID, c.Position)
+			}
+			occupied[c.Position] = c
+		}
+		carts = append(carts, c)
+	}
+	sim.Ticks = s.Ticks
+	sim.Carts = carts
+	sim.Occupied = occupied
+	sim.Collisions = append([]image.Point{}, s.Collisions...)
+	return nil
+}
+
+func (sim *Simulation) MarshalJSON() ([]byte, error) {
+	return json.Marshal(sim.Snapshot())
+}
+
+// UnmarshalJSON restores a snapshot onto the simulation's existing track.
+// The zero Simulation has no track, so it can't be unmarshalled into.
+func (sim *Simulation) UnmarshalJSON(data []byte) error {
+	var s Snapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	return sim.Restore(&s)
+}
+
+// Diff describes every difference between two snapshots. The carts
+// are matched up by their IDs.
+func Diff(a, b *Snapshot) []string {
+	var diffs []string
+	if a.Ticks != b.Ticks {
+		diffs = append(diffs, fmt.Sprintf("ticks: %d != %d", a.Ticks, b.Ticks))
+	}
+	var (
+		ids    []int
+		acarts = map[int]CartState{}
+		bcarts = map[int]CartState{}
+	)
+	for _, c := range a.Carts {
+		acarts[c.ID] = c
+		ids = append(ids, c.ID)
+	}
+	for _, c := range b.Carts {
+		bcarts[c.ID] = c
+		if _, ok := acarts[c.ID]; !ok {
+			ids = append(ids, c.ID)
+		}
+	}
+	sort.Ints(ids)
+	for _, id := range ids {
+		ac, aok := acarts[id]
+		bc, bok := bcarts[id]
+		switch {
+		case !bok:
+			diffs = append(diffs, fmt.Sprintf("cart %d: missing from second snapshot", id))
+		case !aok:
+			diffs = append(diffs, fmt.Sprintf("cart %d: missing from first snapshot", id))
+		default:
+			if ac.Position != bc.Position {
+				diffs = append(diffs, fmt.Sprintf("cart %d: position %v != %v", id, ac.Position, bc.Position))
+			}
+			if ac.Direction != bc.Direction {
+				diffs = append(diffs, fmt.Sprintf("cart %d: direction %v != %v", id, ac.Direction, bc.Direction))
+			}
+			if ac.Intersections != bc.Intersections {
+				diffs = append(diffs, fmt.Sprintf("cart %d: intersections %d != %d", id, ac.Intersections, bc.Intersections))
+			}
+			if ac.Crashed != bc.Crashed {
+				diffs = append(diffs, fmt.Sprintf("cart %d: crashed %t != %t", id, ac.Crashed, bc.Crashed))
+			}
+		}
+	}
+	for i := 0; i < len(a.Collisions) || i < len(b.Collisions); i++ {
+		switch {
+		case i >= len(a.Collisions):
+			diffs = append(diffs, fmt.Sprintf("collision %d: missing from first snapshot, %v in second", i, b.Collisions[i]))
+		case i >= len(b.Collisions):
+			diffs = append(diffs, fmt.Sprintf("collision %d: %v in first, missing from second snapshot", i, a.Collisions[i]))
+		case a.Collisions[i] != b.Collisions[i]:
+			diffs = append(diffs, fmt.Sprintf("collision %d: %v != %v", i, a.Collisions[i], b.Collisions[i]))
+		}
+	}
+	return diffs
+}
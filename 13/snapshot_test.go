@@ -0,0 +1,49 @@
+package day13
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	sim, err := Load(readCanvas(t, "input.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sim.TickN(100)
+	data, err := json.Marshal(sim)
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored, err := Load(readCanvas(t, "input.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, restored); err != nil {
+		t.Fatal(err)
+	}
+	if diffs := Diff(sim.Snapshot(), restored.Snapshot()); len(diffs) > 0 {
+		t.Fatalf("restored snapshot differs: %v", diffs)
+	}
+	for i := 0; i < 1000; i++ {
+		sim.Tick()
+		restored.Tick()
+		if diffs := Diff(sim.Snapshot(), restored.Snapshot()); len(diffs) > 0 {
+			t.Fatalf("tick %d: restored simulation differs: %v", sim.Ticks, diffs)
+		}
+	}
+}
+
+func TestSnapshotWithoutTrack(t *testing.T) {
+	sim, err := Load(readCanvas(t, "example2.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(sim)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, &Simulation{}); err == nil {
+		t.Fatal("expected an error restoring onto a simulation without a track")
+	}
+}
@@ -0,0 +1,90 @@
+// Package advent is a registry of the daily puzzle solutions.
+// Each day registers its Solver from an init function, so importing
+// a day's package is enough to make it available.
+package advent
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Input is a day's parsed puzzle input. Its concrete type is
+// specific to the Solver which produced it.
+type Input interface{}
+
+// Solver solves both parts of a day's puzzle
+type Solver interface {
+	Parse(r io.Reader) (Input, error)
+	PartOne(in Input) (string, error)
+	PartTwo(in Input) (string, error)
+}
+
+// Animator is implemented by solvers which can replay their solution in
+// a terminal. The frames are written to w at fps frames per second and
+// the keys pressed by the user are received from keys.
+type Animator interface {
+	Animate(in Input, w io.Writer, keys <-chan byte, fps int) error
+}
+
+// MaxFPS is the highest frame rate an Animator has to support
+const MaxFPS = 1000
+
+// Exporter is implemented by solvers which can write their puzzle
+// input in other formats, such as a graph description.
+type Exporter interface {
+	Export(in Input, w io.Writer, format string) error
+}
+
+// Configurer is implemented by solvers with options that change how
+// the puzzle is solved. It returns a copy of in with the option set.
+type Configurer interface {
+	Configure(in Input, name, value string) (Input, error)
+}
+
+// Part returns the function which solves part n of s
+func Part(s Solver, n int) (func(Input) (string, error), bool) {
+	switch n {
+	case 1:
+		return s.PartOne, true
+	case 2:
+		return s.PartTwo, true
+	default:
+		return nil, false
+	}
+}
+
+var solvers = map[int]Solver{}
+
+var slow = map[int]bool{10: true}
+
+// Slow reports whether a day takes too long to solve on its input
+// to be included in routine checks and benchmarks
+func Slow(day int) bool {
+	return slow[day]
+}
+
+// Register makes a day's solver available by its number.
+// It panics if the same day is registered twice.
+func Register(day int, s Solver) {
+	if _, ok := solvers[day]; ok {
+		panic(fmt.Sprintf("advent: day %d registered twice", day))
+	}
+	solvers[day] = s
+}
+
+// Lookup returns the solver registered for day
+func Lookup(day int) (Solver, bool) {
+	s, ok := solvers[day]
+	return s, ok
+}
+
+// Days returns the registered day numbers in order
+func Days() []int {
+	var dd []int
+	for day := range solvers {
+		dd = append(dd, day)
+	}
+	sort.Ints(dd)
+	return dd
+}
@@ -0,0 +1,37 @@
+package advent
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// Answer records the expected solutions for one of a day's inputs.
+// An empty part isn't checked, which allows for examples that were
+// only published for one of the parts.
+type Answer struct {
+	Day     int    `json:"day"`
+	Input   string `json:"input"`
+	PartOne string `json:"part_one,omitempty"`
+	PartTwo string `json:"part_two,omitempty"`
+}
+
+// Part returns the expected solution for part n
+func (a Answer) Part(n int) string {
+	switch n {
+	case 1:
+		return a.PartOne
+	case 2:
+		return a.PartTwo
+	default:
+		return ""
+	}
+}
+
+// ReadAnswers decodes a JSON array of answers
+func ReadAnswers(r io.Reader) ([]Answer, error) {
+	var aa []Answer
+	if err := json.NewDecoder(r).Decode(&aa); err != nil {
+		return nil, err
+	}
+	return aa, nil
+}
@@ -0,0 +1,82 @@
+package advent_test
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/icholy/advent"
+
+	_ "github.com/icholy/advent/01"
+	_ "github.com/icholy/advent/02"
+	_ "github.com/icholy/advent/03"
+	_ "github.com/icholy/advent/04"
+	_ "github.com/icholy/advent/05"
+	_ "github.com/icholy/advent/06"
+	_ "github.com/icholy/advent/07"
+	_ "github.com/icholy/advent/08"
+	_ "github.com/icholy/advent/09"
+	_ "github.com/icholy/advent/10"
+	_ "github.com/icholy/advent/11"
+	_ "github.com/icholy/advent/12"
+	_ "github.com/icholy/advent/13"
+)
+
+func readAnswers(t testing.TB) []advent.Answer {
+	f, err := os.Open("answers.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	answers, err := advent.ReadAnswers(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return answers
+}
+
+// parseAnswer parses the input of a recorded answer
+func parseAnswer(t testing.TB, a advent.Answer) (advent.Solver, advent.Input) {
+	s, ok := advent.Lookup(a.Day)
+	if !ok {
+		t.Fatalf("day %d is not registered", a.Day)
+	}
+	f, err := os.Open(a.Input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	input, err := s.Parse(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, input
+}
+
+func TestAnswers(t *testing.T) {
+	for _, a := range readAnswers(t) {
+		for n := 1; n <= 2; n++ {
+			a, n, want := a, n, a.Part(n)
+			if want == "" {
+				continue
+			}
+			t.Run(fmt.Sprintf("%02d/%s/part%d", a.Day, a.Input, n), func(t *testing.T) {
+				if testing.Short() && advent.Slow(a.Day) {
+					t.Skip("slow")
+				}
+				s, input := parseAnswer(t, a)
+				solve, ok := advent.Part(s, n)
+				if !ok {
+					t.Fatalf("day %d has no part %d", a.Day, n)
+				}
+				got, err := solve(input)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got != want {
+					t.Fatalf("got %q, want %q", got, want)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package advent_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/icholy/advent"
+)
+
+// BenchmarkParts solves both parts of every day's input.txt.
+// The slow days take hours, so they're skipped with -short.
+func BenchmarkParts(b *testing.B) {
+	for _, day := range advent.Days() {
+		for n := 1; n <= 2; n++ {
+			day, n := day, n
+			b.Run(fmt.Sprintf("%02d/part%d", day, n), func(b *testing.B) {
+				if testing.Short() && advent.Slow(day) {
+					b.Skip("slow")
+				}
+				input := filepath.Join(fmt.Sprintf("%02d", day), "input.txt")
+				if _, err := os.Stat(input); err != nil {
+					b.Skip(err)
+				}
+				s, in := parseAnswer(b, advent.Answer{Day: day, Input: input})
+				solve, ok := advent.Part(s, n)
+				if !ok {
+					b.Fatalf("day %d has no part %d", day, n)
+				}
+				b.ReportAllocs()
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					if _, err := solve(in); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
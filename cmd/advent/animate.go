@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+
+	"github.com/icholy/advent"
+)
+
+// animate replays a day's solution in the terminal. The keys are read
+// from stdin unless the puzzle input came from there too.
+func animate(day int, s advent.Solver, input advent.Input, stdin bool, fps int) error {
+	a, ok := s.(advent.Animator)
+	if !ok {
+		return fmt.Errorf("day %d can't be animated", day)
+	}
+	if stdin {
+		return a.Animate(input, os.Stdout, nil, fps)
+	}
+	restore, err := cbreak()
+	if err != nil {
+		return err
+	}
+	defer restore()
+	// cbreak still lets ^C through, so put the terminal back before exiting
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt)
+	go func() {
+		<-sig
+		restore()
+		os.Exit(130)
+	}()
+	return a.Animate(input, os.Stdout, readKeys(), fps)
+}
+
+// readKeys sends every byte read from stdin
+func readKeys() <-chan byte {
+	keys := make(chan byte)
+	go func() {
+		defer close(keys)
+		buf := make([]byte, 1)
+		for {
+			if _, err := os.Stdin.Read(buf); err != nil {
+				return
+			}
+			keys <- buf[0]
+		}
+	}()
+	return keys
+}
+
+// cbreak makes the terminal pass keys through as they're pressed
+// without echoing them. The returned function restores the old settings.
+func cbreak() (func(), error) {
+	old, err := stty("-g")
+	if err != nil {
+		return nil, fmt.Errorf("stdin is not a terminal: %v", err)
+	}
+	if _, err := stty("cbreak", "-echo"); err != nil {
+		return nil, err
+	}
+	return func() { stty(strings.TrimSpace(old)) }, nil
+}
+
+func stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	out, err := cmd.Output()
+	return string(out), err
+}
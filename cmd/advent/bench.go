@@ -0,0 +1,149 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/icholy/advent"
+)
+
+// Timing is a benchmark measurement of one part of a day's puzzle.
+// A list of these is what gets saved as a baseline.
+type Timing struct {
+	Day         int   `json:"day"`
+	Part        int   `json:"part"`
+	NsPerOp     int64 `json:"ns_per_op"`
+	AllocsPerOp int64 `json:"allocs_per_op"`
+	BytesPerOp  int64 `json:"bytes_per_op"`
+}
+
+func (t Timing) key() [2]int { return [2]int{t.Day, t.Part} }
+
+func bench(args []string) error {
+	fs := flag.NewFlagSet("bench", flag.ExitOnError)
+	only := fs.Int("day", 0, "only benchmark this day")
+	part := fs.Int("part", 0, "only benchmark this part")
+	baseline := fs.String("baseline", "", "compare against timings saved by --save")
+	save := fs.String("save", "", "save the timings to this file")
+	fs.Parse(args)
+
+	previous := map[[2]int]Timing{}
+	if *baseline != "" {
+		tt, err := readTimings(*baseline)
+		if err != nil {
+			return err
+		}
+		for _, t := range tt {
+			previous[t.key()] = t
+		}
+	}
+
+	var days []int
+	for _, day := range advent.Days() {
+		switch {
+		case *only != 0 && day != *only:
+		case *only == 0 && advent.Slow(day):
+			fmt.Fprintf(os.Stderr, "skipping day %d because it's slow, use --day %d to benchmark it\n", day, day)
+		default:
+			days = append(days, day)
+		}
+	}
+	if len(days) == 0 {
+		return fmt.Errorf("day %d is not registered", *only)
+	}
+	parts := []int{1, 2}
+	if *part != 0 {
+		parts = []int{*part}
+	}
+
+	// rows are printed as they're measured because some parts are slow,
+	// so the columns have fixed widths instead of being aligned afterwards.
+	const row = "%3v %4v %14v %12v %10v %8v\n"
+	fmt.Printf(row, "day", "part", "ns/op", "B/op", "allocs/op", "delta")
+	var timings []Timing
+	for _, day := range days {
+		for _, n := range parts {
+			t, err := benchPart(day, n)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "day %d part %d: %v\n", day, n, err)
+				continue
+			}
+			timings = append(timings, t)
+			delta := "-"
+			if p, ok := previous[t.key()]; ok && p.NsPerOp != 0 {
+				delta = fmt.Sprintf("%+.1f%%", float64(t.NsPerOp-p.NsPerOp)/float64(p.NsPerOp)*100)
+			}
+			fmt.Printf(row, fmt.Sprintf("%02d", t.Day), t.Part, t.NsPerOp, t.BytesPerOp, t.AllocsPerOp, delta)
+		}
+	}
+	if *save != "" {
+		return writeTimings(*save, timings)
+	}
+	return nil
+}
+
+// benchPart parses the day's default input and benchmarks part n.
+// The part is solved once beforehand so that failures are reported
+// instead of showing up as an empty result.
+func benchPart(day, n int) (Timing, error) {
+	s, ok := advent.Lookup(day)
+	if !ok {
+		return Timing{}, fmt.Errorf("not registered")
+	}
+	f, err := openInput(DefaultInput(day))
+	if err != nil {
+		return Timing{}, err
+	}
+	defer f.Close()
+	input, err := s.Parse(f)
+	if err != nil {
+		return Timing{}, err
+	}
+	solve, ok := advent.Part(s, n)
+	if !ok {
+		return Timing{}, fmt.Errorf("no part %d", n)
+	}
+	if _, err := solve(input); err != nil {
+		return Timing{}, err
+	}
+	res := testing.Benchmark(func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := solve(input); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	return Timing{
+		Day:         day,
+		Part:        n,
+		NsPerOp:     res.NsPerOp(),
+		AllocsPerOp: res.AllocsPerOp(),
+		BytesPerOp:  res.AllocedBytesPerOp(),
+	}, nil
+}
+
+func readTimings(file string) ([]Timing, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var tt []Timing
+	if err := json.NewDecoder(f).Decode(&tt); err != nil {
+		return nil, fmt.Errorf("%s: %v", file, err)
+	}
+	return tt, nil
+}
+
+func writeTimings(file string, tt []Timing) error {
+	data, err := json.MarshalIndent(tt, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, append(data, '\n'), 0644)
+}
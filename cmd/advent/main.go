@@ -0,0 +1,343 @@
+package main
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/icholy/advent"
+
+	_ "github.com/icholy/advent/01"
+	_ "github.com/icholy/advent/02"
+	_ "github.com/icholy/advent/03"
+	_ "github.com/icholy/advent/04"
+	_ "github.com/icholy/advent/05"
+	_ "github.com/icholy/advent/06"
+	_ "github.com/icholy/advent/07"
+	_ "github.com/icholy/advent/08"
+	_ "github.com/icholy/advent/09"
+	_ "github.com/icholy/advent/10"
+	_ "github.com/icholy/advent/11"
+	_ "github.com/icholy/advent/12"
+	_ "github.com/icholy/advent/13"
+)
+
+const usage = `usage: advent <command> [arguments]
+
+commands:
+  run <day> [--part n] [--input file] [--format text|json] [--set name=value]
+                                        solve a day's puzzle, "-" reads stdin
+  run <day> --animate [--fps n] [--input file]
+                                        replay a solution, space pauses, n steps, q quits
+  list                                  list the registered days
+  verify [--answers file] [--day n]     check the solvers against recorded answers
+  bench [--day n] [--part n] [--baseline file] [--save file]
+                                        time each part and compare with a baseline
+  export <day> [--format name] [--input file]
+                                        write a day's input in another format
+`
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("advent: ")
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	var err error
+	switch cmd, args := os.Args[1], os.Args[2:]; cmd {
+	case "run":
+		err = run(args)
+	case "list":
+		err = list()
+	case "verify":
+		err = verify(args)
+	case "bench":
+		err = bench(args)
+	case "export":
+		err = export(args)
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// DefaultInput returns the path of a day's input file relative
+// to the repository root
+func DefaultInput(day int) string {
+	return filepath.Join(fmt.Sprintf("%02d", day), "input.txt")
+}
+
+// openInput opens the named puzzle input. The name "-" reads from stdin
+// and files ending in ".gz" are decompressed as they're read.
+func openInput(name string) (io.ReadCloser, error) {
+	if name == "-" {
+		return ioutil.NopCloser(os.Stdin), nil
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	if filepath.Ext(name) != ".gz" {
+		return f, nil
+	}
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return gzipFile{zr, f}, nil
+}
+
+// gzipFile closes both the decompressor and the file underneath it
+type gzipFile struct {
+	*gzip.Reader
+	f *os.File
+}
+
+func (gf gzipFile) Close() error {
+	if err := gf.Reader.Close(); err != nil {
+		gf.f.Close()
+		return err
+	}
+	return gf.f.Close()
+}
+
+// parseDay reads the day number from the front of args and returns
+// the remaining arguments. This lets flags come after the day.
+func parseDay(args []string) (int, []string, error) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return 0, nil, fmt.Errorf("missing day")
+	}
+	day, err := strconv.Atoi(args[0])
+	if err != nil {
+		return 0, nil, fmt.Errorf("invalid day: %q", args[0])
+	}
+	return day, args[1:], nil
+}
+
+// result is a single part's answer as written by --format json
+type result struct {
+	Day       int    `json:"day"`
+	Part      int    `json:"part"`
+	Answer    string `json:"answer"`
+	ElapsedNS int64  `json:"elapsed_ns"`
+}
+
+func run(args []string) error {
+	day, args, err := parseDay(args)
+	if err != nil {
+		return err
+	}
+	fs := flag.NewFlagSet("run", flag.ExitOnError)
+	part := fs.Int("part", 0, "part to solve, 0 solves both")
+	file := fs.String("input", DefaultInput(day), "puzzle input file")
+	format := fs.String("format", "text", "output format: text or json")
+	anim := fs.Bool("animate", false, "replay the solution in the terminal instead of printing answers")
+	fps := fs.Int("fps", 10, "frames per second for --animate")
+	var opts options
+	fs.Var(&opts, "set", "set a day's option as name=value, can be repeated")
+	fs.Parse(args)
+
+	if *format != "text" && *format != "json" {
+		return fmt.Errorf("invalid format: %q", *format)
+	}
+	s, ok := advent.Lookup(day)
+	if !ok {
+		return fmt.Errorf("day %d is not registered", day)
+	}
+	parts := []int{1, 2}
+	if *part != 0 {
+		parts = []int{*part}
+	}
+	f, err := openInput(*file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	input, err := s.Parse(f)
+	if err != nil {
+		return fmt.Errorf("day %d: %v", day, err)
+	}
+	if input, err = configure(s, input, opts); err != nil {
+		return fmt.Errorf("day %d: %v", day, err)
+	}
+	if *anim {
+		if *fps < 1 || *fps > advent.MaxFPS {
+			return fmt.Errorf("--fps must be between 1 and %d", advent.MaxFPS)
+		}
+		return animate(day, s, input, *file == "-", *fps)
+	}
+	enc := json.NewEncoder(os.Stdout)
+	for _, n := range parts {
+		solve, ok := advent.Part(s, n)
+		if !ok {
+			return fmt.Errorf("day %d has no part %d", day, n)
+		}
+		start := time.Now()
+		answer, err := solve(input)
+		if err != nil {
+			return fmt.Errorf("day %d part %d: %v", day, n, err)
+		}
+		elapsed := time.Since(start)
+		if *format == "json" {
+			if err := enc.Encode(result{day, n, answer, elapsed.Nanoseconds()}); err != nil {
+				return err
+			}
+		} else {
+			fmt.Printf("Answer (Part %d): %s\n", n, answer)
+		}
+	}
+	return nil
+}
+
+// options are the name=value pairs passed with --set
+type options []string
+
+func (o *options) String() string { return strings.Join(*o, ",") }
+
+func (o *options) Set(s string) error {
+	if !strings.Contains(s, "=") {
+		return fmt.Errorf("expected name=value, got %q", s)
+	}
+	*o = append(*o, s)
+	return nil
+}
+
+// configure sets each of the options on the input
+func configure(s advent.Solver, input advent.Input, opts options) (advent.Input, error) {
+	if len(opts) == 0 {
+		return input, nil
+	}
+	c, ok := s.(advent.Configurer)
+	if !ok {
+		return nil, fmt.Errorf("no options can be set")
+	}
+	for _, opt := range opts {
+		var (
+			i           = strings.Index(opt, "=")
+			name, value = opt[:i], opt[i+1:]
+			err         error
+		)
+		if input, err = c.Configure(input, name, value); err != nil {
+			return nil, fmt.Errorf("%s: %v", name, err)
+		}
+	}
+	return input, nil
+}
+
+func export(args []string) error {
+	day, args, err := parseDay(args)
+	if err != nil {
+		return err
+	}
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	format := fs.String("format", "dot", "output format, which depends on the day")
+	file := fs.String("input", DefaultInput(day), "puzzle input file")
+	fs.Parse(args)
+
+	s, ok := advent.Lookup(day)
+	if !ok {
+		return fmt.Errorf("day %d is not registered", day)
+	}
+	e, ok := s.(advent.Exporter)
+	if !ok {
+		return fmt.Errorf("day %d can't be exported", day)
+	}
+	f, err := openInput(*file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	input, err := s.Parse(f)
+	if err != nil {
+		return fmt.Errorf("day %d: %v", day, err)
+	}
+	return e.Export(input, os.Stdout, *format)
+}
+
+func list() error {
+	for _, day := range advent.Days() {
+		fmt.Printf("%02d\n", day)
+	}
+	return nil
+}
+
+func verify(args []string) error {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	file := fs.String("answers", "answers.json", "recorded answers")
+	only := fs.Int("day", 0, "only verify this day")
+	fs.Parse(args)
+
+	f, err := os.Open(*file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	answers, err := advent.ReadAnswers(f)
+	if err != nil {
+		return fmt.Errorf("%s: %v", *file, err)
+	}
+	var failed int
+	for _, a := range answers {
+		if *only != 0 && a.Day != *only {
+			continue
+		}
+		for _, err := range check(a) {
+			fmt.Printf("FAIL %02d %s: %v\n", a.Day, a.Input, err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d failed", failed)
+	}
+	return nil
+}
+
+// check solves both parts of a.Input and returns an error
+// for every part which doesn't match the recorded answer
+func check(a advent.Answer) []error {
+	s, ok := advent.Lookup(a.Day)
+	if !ok {
+		return []error{fmt.Errorf("day %d is not registered", a.Day)}
+	}
+	f, err := openInput(a.Input)
+	if err != nil {
+		return []error{err}
+	}
+	defer f.Close()
+	input, err := s.Parse(f)
+	if err != nil {
+		return []error{err}
+	}
+	var errs []error
+	for n := 1; n <= 2; n++ {
+		want := a.Part(n)
+		if want == "" {
+			continue
+		}
+		solve, _ := advent.Part(s, n)
+		got, err := solve(input)
+		switch {
+		case err != nil:
+			errs = append(errs, fmt.Errorf("part %d: %v", n, err))
+		case got != want:
+			errs = append(errs, fmt.Errorf("part %d: got %q, want %q", n, got, want))
+		default:
+			fmt.Printf("ok   %02d %s: part %d\n", a.Day, a.Input, n)
+		}
+	}
+	return errs
+}
@@ -0,0 +1,7 @@
+module github.com/icholy/advent
+
+go 1.21
+
+// The dependencies aren't pinned yet. Pin them and commit go.sum with:
+//
+//	go get github.com/icholy/draw@latest github.com/spakin/disjoint@latest